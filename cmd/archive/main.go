@@ -0,0 +1,156 @@
+package main
+
+import (
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/yourname/hyper-sniper-indexer/internal/archive"
+	"github.com/yourname/hyper-sniper-indexer/internal/config"
+)
+
+const usage = `Использование:
+  archive [-dir DIR] list
+  archive [-dir DIR] show <code_hash>
+  archive [-dir DIR] dump [-part code|data] [-out FILE] <code_hash>
+
+Без -dir каталог берётся из archive.dir конфига (CONFIG_PATH или config.yaml).
+dump без -out печатает BOC в hex.
+`
+
+// Просмотр архива кода неизвестных контрактов.
+func main() {
+	dirFlag := flag.String("dir", "", "каталог архива")
+	flag.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	flag.Parse()
+
+	if flag.NArg() < 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	arc, err := archive.New(archiveDir(*dirFlag))
+	if err != nil {
+		fatal(err)
+	}
+
+	args := flag.Args()
+	switch args[0] {
+	case "list":
+		err = list(arc)
+	case "show":
+		err = show(arc, args[1:])
+	case "dump":
+		err = dump(arc, args[1:])
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		fatal(err)
+	}
+}
+
+func list(arc *archive.Archive) error {
+	entries, err := arc.List()
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "FIRST_SEEN\tCODE_HASH\tKIND\tMINTER_TYPE\tSEQNO\tCODE\tDATA\tFIRST_ADDRESS")
+	for _, e := range entries {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%d\t%d\t%s\n",
+			e.FirstSeen.Format(time.RFC3339), e.CodeHash, e.Kind, dash(e.MinterType),
+			e.Seqno, e.CodeSize, e.DataSize, e.FirstAddress,
+		)
+	}
+	return w.Flush()
+}
+
+func show(arc *archive.Archive, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("show: нужен code_hash")
+	}
+
+	e, err := arc.Get(args[0])
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("code_hash:     %s\n", e.CodeHash)
+	fmt.Printf("kind:          %s\n", e.Kind)
+	fmt.Printf("minter_type:   %s\n", dash(e.MinterType))
+	fmt.Printf("first_address: %s\n", e.FirstAddress)
+	fmt.Printf("first_seen:    %s\n", e.FirstSeen.Format(time.RFC3339))
+	fmt.Printf("seqno:         %d\n", e.Seqno)
+	fmt.Printf("tx:            %s (lt %d)\n", dash(e.TxHash), e.TxLT)
+	fmt.Printf("code/data:     %d / %d байт\n", e.CodeSize, e.DataSize)
+	return nil
+}
+
+func dump(arc *archive.Archive, args []string) error {
+	fs := flag.NewFlagSet("dump", flag.ContinueOnError)
+	part := fs.String("part", "code", "code или data")
+	out := fs.String("out", "", "файл для BOC (по умолчанию hex в stdout)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("dump: нужен code_hash")
+	}
+
+	var boc []byte
+	var err error
+	switch *part {
+	case "code":
+		boc, err = arc.Code(fs.Arg(0))
+	case "data":
+		boc, err = arc.Data(fs.Arg(0))
+	default:
+		return fmt.Errorf("dump: неизвестная часть %q", *part)
+	}
+	if err != nil {
+		return err
+	}
+	if boc == nil {
+		return fmt.Errorf("dump: StateInit был без данных")
+	}
+
+	if *out == "" {
+		fmt.Println(hex.EncodeToString(boc))
+		return nil
+	}
+	return os.WriteFile(*out, boc, 0o644)
+}
+
+// archiveDir выбирает каталог: флаг, затем конфиг, затем значение по умолчанию.
+func archiveDir(flagDir string) string {
+	if flagDir != "" {
+		return flagDir
+	}
+
+	path := os.Getenv("CONFIG_PATH")
+	if path == "" {
+		path = "config.yaml"
+	}
+	if cfg, err := config.Load(path); err == nil && cfg.Archive.Dir != "" {
+		return cfg.Archive.Dir
+	}
+	return "data/archive"
+}
+
+func dash(v string) string {
+	if v == "" {
+		return "—"
+	}
+	return v
+}
+
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, "archive:", err)
+	os.Exit(1)
+}
@@ -8,13 +8,20 @@ import (
 	"os/signal"
 	"syscall"
 
+	"github.com/yourname/hyper-sniper-indexer/internal/api"
+	"github.com/yourname/hyper-sniper-indexer/internal/archive"
 	"github.com/yourname/hyper-sniper-indexer/internal/config"
 	"github.com/yourname/hyper-sniper-indexer/internal/detector"
+	"github.com/yourname/hyper-sniper-indexer/internal/holders"
 	"github.com/yourname/hyper-sniper-indexer/internal/indexer"
 	"github.com/yourname/hyper-sniper-indexer/internal/notifier"
 	"github.com/yourname/hyper-sniper-indexer/internal/processor"
+	"github.com/yourname/hyper-sniper-indexer/internal/rules"
 	"github.com/yourname/hyper-sniper-indexer/internal/storage"
+	"github.com/yourname/hyper-sniper-indexer/internal/trace"
+	"github.com/yourname/hyper-sniper-indexer/internal/tracker"
 	"github.com/yourname/hyper-sniper-indexer/internal/utils"
+	"github.com/yourname/hyper-sniper-indexer/internal/watchlist"
 	"github.com/yourname/hyper-sniper-indexer/pkg/ton"
 	"go.uber.org/zap"
 )
@@ -70,6 +77,9 @@ func main() {
 	// Создаём детектор (передаём TON клиент как MetadataFetcher)
 	det := detector.NewDetector(tonClient, logger)
 	det.LoadRealCodeHashes() // Загружаем реальные хэши
+	for hash, desc := range cfg.Detector.WalletCodeHashes {
+		det.AddWalletCodeHash(hash, desc)
+	}
 	logger.Info("✅ Детектор инициализирован", zap.Int("known_hashes", len(det.GetKnownHashes())))
 
 	// Создаём нотификатор
@@ -82,6 +92,173 @@ func main() {
 
 	// Создаём процессор
 	proc := processor.NewProcessor(det, tonClient, store.Cache, ntf, logger)
+	proc.SetRecheckQueue(store.Cache)
+	proc.SetFingerprinter(detector.NewFingerprinter(tonClient, cfg.Detector.FingerprintMethods, config.DurationOr(cfg.Detector.FingerprintTimeout, 0), logger))
+	protected := detector.DefaultProtectedTokens()
+	for _, token := range cfg.Detector.ProtectedTokens {
+		protected = append(protected, detector.ProtectedToken{Symbol: token.Symbol, Name: token.Name, Minter: token.Minter})
+	}
+	proc.SetImpersonationChecker(detector.NewImpersonationChecker(protected, logger))
+	proc.SetDeployerReputation(detector.NewDeployerReputation(store.Cache, cfg.Detector.DeployerAllowlist, cfg.Detector.DeployerBlocklist, logger))
+	routers := detector.DefaultDexRouters()
+	for _, r := range cfg.Dex.Routers {
+		addr, err := ton.ParseAddress(r.Address)
+		if err != nil {
+			logger.Warn("пропущен роутер DEX", zap.String("address", r.Address), zap.Error(err))
+			continue
+		}
+		routers[ton.RawAddress(addr)] = r.DEX
+	}
+	proc.SetDexRouters(routers)
+	lockers := make(map[string]string, len(cfg.Dex.LPLockers))
+	for _, l := range cfg.Dex.LPLockers {
+		addr, err := ton.ParseAddress(l.Address)
+		if err != nil {
+			logger.Warn("пропущен локер LP", zap.String("address", l.Address), zap.Error(err))
+			continue
+		}
+		lockers[ton.RawAddress(addr)] = l.Name
+	}
+	proc.SetLPLockers(lockers)
+	if len(cfg.Detector.Launchpads) > 0 {
+		proc.AddDetector(detector.NewLaunchpadDetector(cfg.Detector.Launchpads, logger))
+		logger.Info("✅ Детектор лаунчпадов включён", zap.Int("code_hashes", len(cfg.Detector.Launchpads)))
+	}
+
+	// Правила уведомлений о деплоях любого класса контрактов
+	if len(cfg.Rules) > 0 {
+		list := make([]rules.Rule, 0, len(cfg.Rules))
+		for _, r := range cfg.Rules {
+			list = append(list, rules.Rule{
+				Name:       r.Name,
+				CodeHashes: r.CodeHashes,
+				Interfaces: r.Interfaces,
+				Kinds:      r.Kinds,
+				Workchains: r.Workchains,
+				Deployers:  r.Deployers,
+				TgChatID:   r.TgChatID,
+				WebhookURL: r.WebhookURL,
+			})
+		}
+		set, err := rules.New(list)
+		if err != nil {
+			logger.Fatal("ошибка в правилах конфига", zap.Error(err))
+		}
+		proc.SetRules(set)
+		logger.Info("✅ Правила деплоев включены", zap.Int("rules", set.Len()))
+	}
+
+	proc.SetTimeouts(processor.Timeouts{
+		Verify:      config.DurationOr(cfg.Timeouts.Verify, 0),
+		AccountWait: config.DurationOr(cfg.Timeouts.AccountWait, 0),
+		Notify:      config.DurationOr(cfg.Timeouts.Notify, 0),
+	})
+
+	// Асинхронный конвейер: очередь событий и отдельная очередь уведомлений
+	if cfg.Pipeline.Enabled {
+		err := proc.SetPipeline(processor.PipelineConfig{
+			QueueSize:         cfg.Pipeline.QueueSize,
+			Workers:           cfg.Pipeline.Workers,
+			VerifyWorkers:     cfg.Pipeline.VerifyWorkers,
+			EnrichWorkers:     cfg.Pipeline.EnrichWorkers,
+			DispatchQueueSize: cfg.Pipeline.DispatchQueueSize,
+			DispatchWorkers:   cfg.Pipeline.DispatchWorkers,
+			Overflow:          cfg.Pipeline.Overflow,
+		})
+		if err != nil {
+			logger.Fatal("ошибка настройки конвейера", zap.Error(err))
+		}
+		logger.Info("✅ Асинхронный конвейер включён", zap.String("overflow", cfg.Pipeline.Overflow))
+	}
+
+	// Спам-кампании: поток однотипных деплоев сворачивается в одну сводку
+	if cfg.Campaign.Enabled {
+		proc.SetCampaigns(detector.NewCampaignDetector(config.DurationOr(cfg.Campaign.Window, 0), cfg.Campaign.Threshold, cfg.Campaign.StandardCodeHashes, logger))
+		logger.Info("✅ Обнаружение спам-кампаний включено")
+	}
+
+	// Архив BOC кода и данных неизвестных контрактов
+	if cfg.Archive.Enabled {
+		arc, err := archive.New(cfg.Archive.Dir)
+		if err != nil {
+			logger.Fatal("ошибка инициализации архива кода", zap.Error(err))
+		}
+		proc.SetArchive(arc)
+		logger.Info("✅ Архив кода включён", zap.String("dir", cfg.Archive.Dir))
+	}
+
+	// Трекер изменений найденных минтеров (supply, админ, content)
+	if cfg.Tracker.Enabled {
+		trk := tracker.New(det, ntf, config.DurationOr(cfg.Tracker.Interval, 0), config.DurationOr(cfg.Tracker.Window, 0), cfg.Tracker.MaxTokens, logger)
+		proc.SetTracker(trk)
+		go trk.Run(ctx)
+		logger.Info("✅ Трекер минтеров включён")
+	}
+
+	// Таблица держателей новых jetton по internal_transfer из потока блоков
+	var holderBook *holders.Tracker
+	if cfg.Holders.Enabled {
+		holderBook = holders.New(det, ntf, config.DurationOr(cfg.Holders.Interval, 0), config.DurationOr(cfg.Holders.Window, 0), cfg.Holders.MaxTokens, logger)
+		proc.SetHolders(holderBook)
+		proc.SetJettonWindow(holderBook.Window())
+		go holderBook.Run(ctx)
+		logger.Info("✅ Таблица держателей включена")
+	}
+
+	// Список наблюдения: конфиг + Redis, транзакции наблюдаемых адресов из потока блоков
+	var watches *watchlist.Watchlist
+	if cfg.Watch.Enabled {
+		watches = watchlist.New(store.Cache, logger)
+		for _, e := range cfg.Watch.Addresses {
+			if err := watches.AddConfig(watchlist.KindAddress, e.Value, e.Label); err != nil {
+				logger.Warn("пропущена запись списка наблюдения", zap.Error(err))
+			}
+		}
+		for _, e := range cfg.Watch.CodeHashes {
+			if err := watches.AddConfig(watchlist.KindCodeHash, e.Value, e.Label); err != nil {
+				logger.Warn("пропущена запись списка наблюдения", zap.Error(err))
+			}
+		}
+		if err := watches.Reload(ctx); err != nil {
+			logger.Warn("не удалось загрузить список наблюдения из Redis", zap.Error(err))
+		}
+		go watches.Run(ctx, config.DurationOr(cfg.Watch.ReloadInterval, 0))
+
+		proc.SetWatchlist(watches)
+		logger.Info("✅ Список наблюдения включён", zap.Int("entries", len(watches.List())))
+	}
+
+	// Транзакции без деплоя: операции недавно найденных jetton и наблюдаемые адреса
+	tonClient.SetTxFilter(func(addr string) bool {
+		return proc.WatchesJetton(addr) || (watches != nil && watches.WatchesAddress(addr))
+	})
+
+	// Трассировка решений по деплоям и служебный HTTP API
+	var decisions *trace.Store
+	if cfg.Trace.Enabled {
+		decisions = trace.NewStore(cfg.Trace.Capacity)
+		proc.SetDecisionStore(decisions)
+	}
+	if cfg.API.Enabled {
+		srv := api.New(cfg.API.Addr, logger)
+		if decisions != nil {
+			srv.SetDecisions(decisions)
+		}
+		if watches != nil {
+			srv.SetWatchlist(watches)
+		}
+		if holderBook != nil {
+			srv.SetHolders(holderBook)
+		}
+		if cfg.Pipeline.Enabled {
+			srv.SetPipeline(proc)
+		}
+		go func() {
+			if err := srv.Run(ctx); err != nil {
+				logger.Error("ошибка HTTP API", zap.Error(err))
+			}
+		}()
+	}
 
 	// Создаём и запускаем сервис индексатора
 	svc := indexer.NewService(cfg, tonClient, proc, logger)
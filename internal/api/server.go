@@ -0,0 +1,254 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/yourname/hyper-sniper-indexer/internal/holders"
+	"github.com/yourname/hyper-sniper-indexer/internal/processor"
+	"github.com/yourname/hyper-sniper-indexer/internal/trace"
+	"github.com/yourname/hyper-sniper-indexer/internal/watchlist"
+	"github.com/yourname/hyper-sniper-indexer/pkg/ton"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultLimit = 100
+	maxLimit     = 1000
+
+	shutdownTimeout = 5 * time.Second
+
+	maxBodyBytes = 16 << 10
+)
+
+// DecisionSource отдаёт трассировку решений по деплоям (реализуется trace.Store).
+type DecisionSource interface {
+	ByAddress(address string) []trace.Decision
+	Recent(limit int) []trace.Decision
+}
+
+// WatchlistSource — список наблюдения (реализуется watchlist.Watchlist).
+type WatchlistSource interface {
+	List() []watchlist.Entry
+	Add(ctx context.Context, entry watchlist.Entry) error
+	Remove(ctx context.Context, kind watchlist.Kind, value string) error
+}
+
+// HolderSource отдаёт распределение держателей (реализуется holders.Tracker).
+type HolderSource interface {
+	Snapshot(ctx context.Context, minter string) (*holders.Distribution, bool)
+}
+
+// PipelineSource отдаёт метрики очередей конвейера (реализуется processor.Processor).
+type PipelineSource interface {
+	PipelineStats() []processor.QueueStats
+}
+
+// Server — служебный HTTP API индексатора.
+type Server struct {
+	addr      string
+	decisions DecisionSource
+	watchlist WatchlistSource
+	holders   HolderSource
+	pipeline  PipelineSource
+	logger    *zap.Logger
+}
+
+// New создаёт сервер на addr (host:port).
+func New(addr string, logger *zap.Logger) *Server {
+	return &Server{
+		addr:   addr,
+		logger: logger,
+	}
+}
+
+// SetDecisions подключает трассировку решений: GET /decisions и /decisions/{address}.
+func (s *Server) SetDecisions(src DecisionSource) {
+	s.decisions = src
+}
+
+// SetWatchlist подключает управление списком наблюдения: GET/POST /watchlist, DELETE /watchlist/{kind}/{value}.
+func (s *Server) SetWatchlist(src WatchlistSource) {
+	s.watchlist = src
+}
+
+// SetHolders подключает распределение держателей: GET /holders/{minter}.
+func (s *Server) SetHolders(src HolderSource) {
+	s.holders = src
+}
+
+// SetPipeline подключает метрики очередей конвейера: GET /pipeline.
+func (s *Server) SetPipeline(src PipelineSource) {
+	s.pipeline = src
+}
+
+// Handler возвращает маршруты API.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /health", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	})
+	if s.decisions != nil {
+		mux.HandleFunc("GET /decisions", s.handleRecentDecisions)
+		mux.HandleFunc("GET /decisions/{address}", s.handleAddressDecisions)
+	}
+	if s.watchlist != nil {
+		mux.HandleFunc("GET /watchlist", s.handleListWatches)
+		mux.HandleFunc("POST /watchlist", s.handleAddWatch)
+		mux.HandleFunc("DELETE /watchlist/{kind}/{value}", s.handleRemoveWatch)
+	}
+	if s.holders != nil {
+		mux.HandleFunc("GET /holders/{minter}", s.handleHolders)
+	}
+	if s.pipeline != nil {
+		mux.HandleFunc("GET /pipeline", func(w http.ResponseWriter, _ *http.Request) {
+			writeJSON(w, http.StatusOK, s.pipeline.PipelineStats())
+		})
+	}
+	return mux
+}
+
+// Run обслуживает запросы до отмены ctx.
+func (s *Server) Run(ctx context.Context) error {
+	srv := &http.Server{
+		Addr:              s.addr,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		srv.Shutdown(shutdownCtx) //nolint:errcheck
+	}()
+
+	s.logger.Info("HTTP API запущен", zap.String("addr", s.addr))
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// handleRecentDecisions отдаёт последние решения: ?limit=N&verdict=rejected.
+func (s *Server) handleRecentDecisions(w http.ResponseWriter, r *http.Request) {
+	limit := defaultLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			writeError(w, http.StatusBadRequest, "некорректный limit")
+			return
+		}
+		limit = min(n, maxLimit)
+	}
+
+	verdict := trace.Verdict(r.URL.Query().Get("verdict"))
+	if verdict == "" {
+		writeJSON(w, http.StatusOK, s.decisions.Recent(limit))
+		return
+	}
+
+	// Фильтр по вердикту: просматриваем весь буфер, отдаём не больше limit
+	out := make([]trace.Decision, 0, limit)
+	for _, d := range s.decisions.Recent(0) {
+		if d.Verdict == verdict {
+			out = append(out, d)
+			if len(out) == limit {
+				break
+			}
+		}
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+// handleAddressDecisions отдаёт все решения по адресу (raw или user-friendly).
+func (s *Server) handleAddressDecisions(w http.ResponseWriter, r *http.Request) {
+	addr, err := ton.ParseAddress(r.PathValue("address"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "некорректный адрес")
+		return
+	}
+
+	decisions := s.decisions.ByAddress(ton.RawAddress(addr))
+	if len(decisions) == 0 {
+		writeError(w, http.StatusNotFound, "по адресу нет решений")
+		return
+	}
+	writeJSON(w, http.StatusOK, decisions)
+}
+
+func (s *Server) handleListWatches(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, s.watchlist.List())
+}
+
+// handleAddWatch добавляет запись: {"kind": "address"|"code_hash", "value": "...", "label": "..."}.
+func (s *Server) handleAddWatch(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Kind  watchlist.Kind `json:"kind"`
+		Value string         `json:"value"`
+		Label string         `json:"label"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyBytes)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "некорректный JSON")
+		return
+	}
+
+	entry, err := watchlist.NewEntry(req.Kind, req.Value, req.Label, watchlist.SourceAPI)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := s.watchlist.Add(r.Context(), entry); err != nil {
+		s.logger.Warn("не удалось добавить запись в список наблюдения", zap.Error(err))
+		writeError(w, http.StatusInternalServerError, "не удалось сохранить запись")
+		return
+	}
+
+	s.logger.Info("запись добавлена в список наблюдения",
+		zap.String("kind", string(entry.Kind)),
+		zap.String("value", entry.Value),
+	)
+	writeJSON(w, http.StatusCreated, entry)
+}
+
+func (s *Server) handleRemoveWatch(w http.ResponseWriter, r *http.Request) {
+	err := s.watchlist.Remove(r.Context(), watchlist.Kind(r.PathValue("kind")), r.PathValue("value"))
+	switch {
+	case errors.Is(err, watchlist.ErrNotFound):
+		writeError(w, http.StatusNotFound, err.Error())
+	case err != nil:
+		writeError(w, http.StatusBadRequest, err.Error())
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// handleHolders отдаёт текущее распределение держателей минтера (raw или user-friendly).
+func (s *Server) handleHolders(w http.ResponseWriter, r *http.Request) {
+	addr, err := ton.ParseAddress(r.PathValue("minter"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "некорректный адрес")
+		return
+	}
+
+	d, ok := s.holders.Snapshot(r.Context(), ton.RawAddress(addr))
+	if !ok {
+		writeError(w, http.StatusNotFound, "минтер не отслеживается")
+		return
+	}
+	writeJSON(w, http.StatusOK, d)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v) //nolint:errcheck
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
@@ -0,0 +1,209 @@
+package archive
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	codeFile = "code.boc"
+	dataFile = "data.boc"
+	metaFile = "meta.json"
+)
+
+// ErrNotFound возвращается, если code_hash нет в архиве.
+var ErrNotFound = errors.New("code_hash нет в архиве")
+
+// Entry — метаданные архивной записи: где и когда код впервые встретился.
+type Entry struct {
+	CodeHash     string    `json:"code_hash"`
+	Kind         string    `json:"kind"`        // тип контракта (jetton_minter, nft_collection, ...)
+	MinterType   string    `json:"minter_type"` // для jetton: MinterType на момент архивации
+	FirstAddress string    `json:"first_address"`
+	Seqno        uint32    `json:"seqno"`
+	TxHash       string    `json:"tx_hash,omitempty"`
+	TxLT         uint64    `json:"tx_lt,omitempty"`
+	FirstSeen    time.Time `json:"first_seen"`
+	CodeSize     int       `json:"code_size"`
+	DataSize     int       `json:"data_size"`
+}
+
+// Archive хранит BOC кода и данных StateInit на диске: <dir>/<code_hash>/{code.boc,data.boc,meta.json}.
+// Сохраняется только первое появление каждого code_hash.
+type Archive struct {
+	dir string
+
+	mu   sync.Mutex
+	seen map[string]bool
+}
+
+// New открывает (и при необходимости создаёт) каталог архива.
+func New(dir string) (*Archive, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("каталог архива не задан")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("не удалось создать каталог архива: %w", err)
+	}
+
+	return &Archive{
+		dir:  dir,
+		seen: make(map[string]bool),
+	}, nil
+}
+
+// Store сохраняет код и данные, если code_hash ещё не в архиве.
+// Возвращает true, если запись создана.
+func (a *Archive) Store(entry Entry, code, data []byte) (bool, error) {
+	codeHash, err := normalizeHash(entry.CodeHash)
+	if err != nil {
+		return false, err
+	}
+	if len(code) == 0 {
+		return false, fmt.Errorf("пустой код для %s", codeHash)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.seen[codeHash] {
+		return false, nil
+	}
+
+	entryDir := filepath.Join(a.dir, codeHash)
+	if _, err := os.Stat(filepath.Join(entryDir, metaFile)); err == nil {
+		a.seen[codeHash] = true
+		return false, nil
+	}
+
+	// Пишем во временный каталог и переименовываем: запись либо целиком есть, либо её нет
+	tmpDir, err := os.MkdirTemp(a.dir, ".tmp-"+codeHash[:8]+"-")
+	if err != nil {
+		return false, err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	entry.CodeHash = codeHash
+	entry.CodeSize = len(code)
+	entry.DataSize = len(data)
+	if entry.FirstSeen.IsZero() {
+		entry.FirstSeen = time.Now().UTC()
+	}
+
+	meta, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return false, err
+	}
+
+	if err := os.WriteFile(filepath.Join(tmpDir, codeFile), code, 0o644); err != nil {
+		return false, err
+	}
+	if len(data) > 0 {
+		if err := os.WriteFile(filepath.Join(tmpDir, dataFile), data, 0o644); err != nil {
+			return false, err
+		}
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, metaFile), meta, 0o644); err != nil {
+		return false, err
+	}
+
+	if err := os.Rename(tmpDir, entryDir); err != nil {
+		return false, fmt.Errorf("не удалось сохранить запись %s: %w", codeHash, err)
+	}
+
+	a.seen[codeHash] = true
+	return true, nil
+}
+
+// Get возвращает метаданные записи.
+func (a *Archive) Get(codeHash string) (*Entry, error) {
+	codeHash, err := normalizeHash(codeHash)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := os.ReadFile(filepath.Join(a.dir, codeHash, metaFile))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	var entry Entry
+	if err := json.Unmarshal(b, &entry); err != nil {
+		return nil, fmt.Errorf("повреждён %s/%s: %w", codeHash, metaFile, err)
+	}
+	return &entry, nil
+}
+
+// Code возвращает BOC кода.
+func (a *Archive) Code(codeHash string) ([]byte, error) {
+	return a.readFile(codeHash, codeFile)
+}
+
+// Data возвращает BOC данных (nil, если StateInit был без данных).
+func (a *Archive) Data(codeHash string) ([]byte, error) {
+	b, err := a.readFile(codeHash, dataFile)
+	if errors.Is(err, ErrNotFound) {
+		if _, metaErr := a.Get(codeHash); metaErr == nil {
+			return nil, nil
+		}
+	}
+	return b, err
+}
+
+// List возвращает все записи, отсортированные по времени первого появления.
+func (a *Archive) List() ([]Entry, error) {
+	dirs, err := os.ReadDir(a.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]Entry, 0, len(dirs))
+	for _, d := range dirs {
+		if !d.IsDir() || strings.HasPrefix(d.Name(), ".") {
+			continue
+		}
+		entry, err := a.Get(d.Name())
+		if err != nil {
+			continue
+		}
+		entries = append(entries, *entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].FirstSeen.Before(entries[j].FirstSeen)
+	})
+	return entries, nil
+}
+
+func (a *Archive) readFile(codeHash, name string) ([]byte, error) {
+	codeHash, err := normalizeHash(codeHash)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := os.ReadFile(filepath.Join(a.dir, codeHash, name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return b, err
+}
+
+// normalizeHash проверяет, что code_hash — 32 байта в hex (он же имя каталога).
+func normalizeHash(codeHash string) (string, error) {
+	codeHash = strings.ToLower(strings.TrimSpace(codeHash))
+	if b, err := hex.DecodeString(codeHash); err != nil || len(b) != 32 {
+		return "", fmt.Errorf("некорректный code_hash: %q", codeHash)
+	}
+	return codeHash, nil
+}
@@ -25,6 +25,18 @@ type Config struct {
 	Postgres PostgresConfig `mapstructure:"postgres"`
 	Redis    RedisConfig    `mapstructure:"redis"`
 	Notifier NotifierConfig `mapstructure:"notifier"`
+	Detector DetectorConfig `mapstructure:"detector"`
+	Tracker  TrackerConfig  `mapstructure:"tracker"`
+	Archive  ArchiveConfig  `mapstructure:"archive"`
+	Trace    TraceConfig    `mapstructure:"trace"`
+	API      APIConfig      `mapstructure:"api"`
+	Watch    WatchConfig    `mapstructure:"watchlist"`
+	Dex      DexConfig      `mapstructure:"dex"`
+	Holders  HoldersConfig  `mapstructure:"holders"`
+	Campaign CampaignConfig `mapstructure:"campaigns"`
+	Pipeline PipelineConfig `mapstructure:"pipeline"`
+	Timeouts TimeoutsConfig `mapstructure:"timeouts"`
+	Rules    []RuleConfig   `mapstructure:"rules"`
 }
 
 // AppConfig содержит сетевые и общие параметры работы индексатора.
@@ -38,7 +50,7 @@ type AppConfig struct {
 
 // PostgresConfig описывает подключения к PostgreSQL для разных сетей.
 type PostgresConfig struct {
-	DSN       string `mapstructure:"dsn"`
+	DSN        string `mapstructure:"dsn"`
 	DSNTestnet string `mapstructure:"dsn_testnet"`
 }
 
@@ -52,6 +64,151 @@ type NotifierConfig struct {
 	TgBotToken string `mapstructure:"tg_bot_token"`
 	TgChatID   string `mapstructure:"tg_chat_id"`
 	WebhookURL string `mapstructure:"webhook_url"`
+
+	// Маршрут для списка наблюдения (пусто — основной чат/webhook)
+	WatchTgChatID   string `mapstructure:"watch_tg_chat_id"`
+	WatchWebhookURL string `mapstructure:"watch_webhook_url"`
+}
+
+// DetectorConfig описывает дополнительные детекторы контрактов.
+type DetectorConfig struct {
+	// Launchpads: code_hash контракта лаунчпада -> название
+	Launchpads map[string]string `mapstructure:"launchpads"`
+
+	// Каталог стандартных jetton-кошельков: hash jetton_wallet_code -> описание.
+	// Пока он пуст, unknown_wallet_code в риск не добавляется
+	WalletCodeHashes map[string]string `mapstructure:"jetton_wallet_code_hashes"`
+
+	// Get-методы для fingerprinting неизвестного кода (пусто — набор по умолчанию)
+	FingerprintMethods []string `mapstructure:"fingerprint_methods"`
+	// Общий дедлайн на пробы одного контракта
+	FingerprintTimeout string `mapstructure:"fingerprint_timeout"`
+
+	// Защищённые токены для поиска подделок (добавляются к встроенным USDT/NOT/STON)
+	ProtectedTokens []ProtectedTokenConfig `mapstructure:"protected_tokens"`
+
+	// Деплоеры: allow — уведомление с приоритетом, block — уведомление подавляется
+	DeployerAllowlist []string `mapstructure:"deployer_allowlist"`
+	DeployerBlocklist []string `mapstructure:"deployer_blocklist"`
+}
+
+// ProtectedTokenConfig — токен и адрес его настоящего минтера.
+type ProtectedTokenConfig struct {
+	Symbol string `mapstructure:"symbol"`
+	Name   string `mapstructure:"name"`
+	Minter string `mapstructure:"minter"`
+}
+
+// TrackerConfig описывает периодическую перепроверку найденных минтеров.
+type TrackerConfig struct {
+	Enabled   bool   `mapstructure:"enabled"`
+	Interval  string `mapstructure:"interval"`   // как часто перечитывать get_jetton_data
+	Window    string `mapstructure:"window"`     // сколько следить за минтером после обнаружения
+	MaxTokens int    `mapstructure:"max_tokens"` // лимит минтеров под наблюдением
+}
+
+// HoldersConfig описывает таблицу держателей новых jetton по переводам из потока блоков.
+type HoldersConfig struct {
+	Enabled   bool   `mapstructure:"enabled"`
+	Interval  string `mapstructure:"interval"`   // как часто отправлять снимки holders_snapshot
+	Window    string `mapstructure:"window"`     // сколько вести таблицу после обнаружения
+	MaxTokens int    `mapstructure:"max_tokens"` // лимит минтеров с таблицей
+}
+
+// CampaignConfig описывает обнаружение спам-кампаний: потока однотипных деплоев
+// по code_hash, деплоеру или name/symbol, который сворачивается в одну сводку.
+type CampaignConfig struct {
+	Enabled   bool   `mapstructure:"enabled"`
+	Window    string `mapstructure:"window"`    // скользящее окно; кампания завершается после окна тишины
+	Threshold int    `mapstructure:"threshold"` // деплоев с одним признаком в окне, чтобы считать кампанией
+
+	// code_hash стандартных минтеров; пусто — кампании по code_hash не ищутся
+	StandardCodeHashes []string `mapstructure:"standard_code_hashes"`
+}
+
+// PipelineConfig описывает асинхронный конвейер обработки событий: очереди стадий dedupe → verify → enrich
+// и очередь доставки уведомлений, каждая со своим пулом воркеров.
+type PipelineConfig struct {
+	Enabled           bool   `mapstructure:"enabled"`
+	QueueSize         int    `mapstructure:"queue_size"`
+	Workers           int    `mapstructure:"workers"`
+	VerifyWorkers     int    `mapstructure:"verify_workers"`
+	EnrichWorkers     int    `mapstructure:"enrich_workers"`
+	DispatchQueueSize int    `mapstructure:"dispatch_queue_size"`
+	DispatchWorkers   int    `mapstructure:"dispatch_workers"`
+	Overflow          string `mapstructure:"overflow"` // block / drop_newest / drop_oldest
+}
+
+// TimeoutsConfig описывает дедлайны стадий обработки и остановки. Пустые значения — по умолчанию.
+type TimeoutsConfig struct {
+	Verify        string `mapstructure:"verify"`         // кэш, code_hash, детекторы и обогащение одного деплоя
+	AccountWait   string `mapstructure:"account_wait"`   // ожидание появления аккаунта на liteserver'е
+	Notify        string `mapstructure:"notify"`         // одна отправка в Telegram или webhook
+	ShutdownGrace string `mapstructure:"shutdown_grace"` // сколько остановка ждёт воркеры после SIGTERM
+}
+
+// ArchiveConfig описывает архив BOC кода неизвестных контрактов.
+type ArchiveConfig struct {
+	Enabled bool   `mapstructure:"enabled"`
+	Dir     string `mapstructure:"dir"`
+}
+
+// TraceConfig описывает хранилище решений по деплоям (почему принят или отклонён).
+type TraceConfig struct {
+	Enabled  bool `mapstructure:"enabled"`
+	Capacity int  `mapstructure:"capacity"` // сколько последних решений держать в памяти
+}
+
+// APIConfig описывает служебный HTTP API.
+type APIConfig struct {
+	Enabled bool   `mapstructure:"enabled"`
+	Addr    string `mapstructure:"addr"`
+}
+
+// WatchConfig описывает список наблюдения. Записи из конфига дополняются записями из Redis (API).
+type WatchConfig struct {
+	Enabled        bool               `mapstructure:"enabled"`
+	Addresses      []WatchEntryConfig `mapstructure:"addresses"`       // деплоеры, админы, кошельки
+	CodeHashes     []WatchEntryConfig `mapstructure:"code_hashes"`     // код контрактов
+	ReloadInterval string             `mapstructure:"reload_interval"` // как часто перечитывать Redis
+}
+
+// WatchEntryConfig — адрес или code_hash с подписью.
+type WatchEntryConfig struct {
+	Value string `mapstructure:"value"`
+	Label string `mapstructure:"label"`
+}
+
+// DexConfig описывает контракты DEX, деплоящие пулы (добавляются к роутеру STON.fi v1 и фабрике DeDust),
+// и локеры LP-токенов.
+type DexConfig struct {
+	Routers   []DexRouterConfig `mapstructure:"routers"`
+	LPLockers []LockerConfig    `mapstructure:"lp_lockers"`
+}
+
+// DexRouterConfig — роутер или фабрика пулов.
+type DexRouterConfig struct {
+	DEX     string `mapstructure:"dex"` // stonfi / dedust
+	Address string `mapstructure:"address"`
+}
+
+// LockerConfig — контракт блокировки LP-токенов.
+type LockerConfig struct {
+	Name    string `mapstructure:"name"`
+	Address string `mapstructure:"address"`
+}
+
+// RuleConfig — правило уведомлений о деплоях любого класса контрактов.
+// Условия объединяются по И, значения внутри списка — по ИЛИ; interfaces требует все методы.
+type RuleConfig struct {
+	Name       string   `mapstructure:"name"`
+	CodeHashes []string `mapstructure:"code_hashes"`
+	Interfaces []string `mapstructure:"interfaces"` // get-методы из fingerprint
+	Kinds      []string `mapstructure:"kinds"`      // jetton_minter / nft_collection / dex_pool / launchpad / unknown
+	Workchains []int32  `mapstructure:"workchains"`
+	Deployers  []string `mapstructure:"deployers"`
+	TgChatID   string   `mapstructure:"tg_chat_id"`  // пусто — основной чат
+	WebhookURL string   `mapstructure:"webhook_url"` // пусто — основной webhook
 }
 
 // Load читает config.yaml и переменные окружения с префиксом HSI.
@@ -94,15 +251,20 @@ func (c *Config) ResolvePostgresDSN() string {
 	return c.Postgres.DSN
 }
 
-// MinterCacheDuration возвращает TTL для кэша минтеров.
-func (c *Config) MinterCacheDuration() time.Duration {
-	d, err := time.ParseDuration(c.App.MinterCacheTTL)
+// DurationOr разбирает длительность из конфига; пустое, некорректное или неположительное значение заменяется def.
+func DurationOr(s string, def time.Duration) time.Duration {
+	d, err := time.ParseDuration(s)
 	if err != nil || d <= 0 {
-		return defaultMinterCacheTTL
+		return def
 	}
 	return d
 }
 
+// MinterCacheDuration возвращает TTL для кэша минтеров.
+func (c *Config) MinterCacheDuration() time.Duration {
+	return DurationOr(c.App.MinterCacheTTL, defaultMinterCacheTTL)
+}
+
 // CatchupDuration возвращает длительность окна для режима catchup.
 // Если catchup_hours = 0, catchup отключён.
 // Если catchup_hours < 0, используется default (24 часа).
@@ -127,6 +289,16 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("notifier.tg_bot_token", "")
 	v.SetDefault("notifier.tg_chat_id", "")
 	v.SetDefault("notifier.webhook_url", "")
+	v.SetDefault("tracker.enabled", false)
+	v.SetDefault("holders.enabled", false)
+	v.SetDefault("campaigns.enabled", false)
+	v.SetDefault("pipeline.enabled", true)
+	v.SetDefault("pipeline.overflow", "block")
+	v.SetDefault("archive.dir", "data/archive")
+	v.SetDefault("trace.enabled", true)
+	v.SetDefault("watchlist.enabled", true)
+	v.SetDefault("trace.capacity", 10000)
+	v.SetDefault("api.addr", "127.0.0.1:8090")
 }
 
 func (c *Config) normalize() error {
@@ -149,4 +321,3 @@ func (c *Config) normalize() error {
 
 	return nil
 }
-
@@ -0,0 +1,273 @@
+package detector
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Признак, по которому деплои объединены в кампанию.
+const (
+	CampaignByCodeHash = "code_hash" // одинаковый код не из списка стандартных (фабрика)
+	CampaignByDeployer = "deployer"
+	CampaignByName     = "name"
+	CampaignBySymbol   = "symbol"
+)
+
+const (
+	defaultCampaignWindow    = 10 * time.Minute
+	defaultCampaignThreshold = 5
+
+	// Лимит отслеживаемых ключей: при переполнении удаляются неактивные
+	campaignMaxKeys = 100000
+
+	// Сколько адресов минтеров кампании отдаём в сводке
+	campaignSampleSize = 5
+)
+
+// Campaign — спам-кампания: поток однотипных деплоев за скользящее окно.
+type Campaign struct {
+	By         string    `json:"by"`    // CampaignBy*
+	Value      string    `json:"value"` // code_hash, raw-адрес деплоера или нормализованные name/symbol
+	Count      int       `json:"count"` // деплоев в кампании, включая те, что прошли до её обнаружения
+	Suppressed int       `json:"suppressed"`
+	Sample     []string  `json:"sample"` // первые минтеры кампании
+	Symbols    []string  `json:"symbols,omitempty"`
+	FirstSeen  time.Time `json:"first_seen"`
+	LastSeen   time.Time `json:"last_seen"`
+	Finished   bool      `json:"finished"`
+}
+
+// campaignKey — деплои по одному признаку в окне и активная кампания по нему.
+type campaignKey struct {
+	times    []time.Time
+	minters  []string
+	symbols  []string
+	campaign *Campaign
+}
+
+// CampaignDetector считает частоту деплоев по code_hash, деплоеру и name/symbol в скользящем окне.
+// Ключ, набравший threshold деплоев за window, становится кампанией: дальнейшие деплои с этим
+// признаком подавляются и учитываются в сводке. Кампания завершается после window без новых деплоев.
+//
+// Стандартный код (token-contract, stablecoin) общий для тысяч легитимных jetton, поэтому code_hash
+// учитывается, только если задан список стандартных code_hash, и только для кода вне этого списка.
+// Встроенный каталог для этого не годится: без реальных хэшей кампанией стал бы любой поток
+// обычных минтеров, и его уведомления подавлялись бы без конца.
+type CampaignDetector struct {
+	window    time.Duration
+	threshold int
+	standard  map[string]bool // code_hash стандартных минтеров; пусто — code_hash не учитывается
+	logger    *zap.Logger
+
+	mu         sync.Mutex
+	keys       map[string]*campaignKey
+	chainTime  time.Time // время блока последнего учтённого деплоя
+	observedAt time.Time // когда он учтён по часам процесса
+}
+
+// NewCampaignDetector создаёт детектор кампаний. Нулевые window/threshold заменяются значениями по умолчанию.
+// standard — code_hash стандартных минтеров (из конфига), без них кампании по code_hash не ищутся.
+func NewCampaignDetector(window time.Duration, threshold int, standard []string, logger *zap.Logger) *CampaignDetector {
+	if window <= 0 {
+		window = defaultCampaignWindow
+	}
+	if threshold <= 1 {
+		threshold = defaultCampaignThreshold
+	}
+	exempt := make(map[string]bool, len(standard))
+	for _, hash := range standard {
+		if hash = strings.ToLower(strings.TrimSpace(hash)); hash != "" {
+			exempt[hash] = true
+		}
+	}
+	return &CampaignDetector{
+		window:    window,
+		threshold: threshold,
+		standard:  exempt,
+		logger:    logger,
+		keys:      make(map[string]*campaignKey),
+	}
+}
+
+// Window возвращает скользящее окно детектора.
+func (c *CampaignDetector) Window() time.Duration {
+	return c.window
+}
+
+// Observe учитывает деплой минтера. now — время блока деплоя: при catchup деплои за часы
+// приходят за секунды и по часам процесса выглядели бы кампанией.
+// Возвращает копию кампании, к которой он относится (nil — деплой обычный),
+// и started = true, если кампания обнаружена этим деплоем.
+// Деплой, продолживший уже идущую кампанию, считается подавленным.
+func (c *CampaignDetector) Observe(meta *Metadata, deployer string, now time.Time) (*Campaign, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Шарды обрабатываются параллельно: время не идёт назад, чтобы окна оставались упорядоченными
+	if now.Before(c.chainTime) {
+		now = c.chainTime
+	} else {
+		c.chainTime, c.observedAt = now, time.Now()
+	}
+
+	if len(c.keys) >= campaignMaxKeys {
+		c.prune(now)
+	}
+
+	var (
+		found   *Campaign
+		started bool
+	)
+	for _, k := range c.campaignKeys(meta, deployer) {
+		key := k[0] + ":" + k[1]
+		entry, ok := c.keys[key]
+		if !ok {
+			if len(c.keys) >= campaignMaxKeys {
+				continue
+			}
+			entry = &campaignKey{}
+			c.keys[key] = entry
+		}
+		entry.add(meta, now, c.window)
+
+		if found != nil {
+			continue
+		}
+		if camp := entry.campaign; camp != nil {
+			camp.Count++
+			camp.Suppressed++
+			camp.LastSeen = now
+			if len(camp.Sample) < campaignSampleSize {
+				camp.Sample = append(camp.Sample, meta.Address)
+			}
+			found = camp
+			continue
+		}
+		if len(entry.times) >= c.threshold {
+			entry.campaign = &Campaign{
+				By:        k[0],
+				Value:     k[1],
+				Count:     len(entry.times),
+				Sample:    append([]string(nil), entry.minters...),
+				Symbols:   append([]string(nil), entry.symbols...),
+				FirstSeen: entry.times[0],
+				LastSeen:  now,
+			}
+			found, started = entry.campaign, true
+			c.logger.Info("обнаружена спам-кампания",
+				zap.String("by", k[0]),
+				zap.String("value", k[1]),
+				zap.Int("count", found.Count),
+			)
+		}
+	}
+
+	if found == nil {
+		return nil, false
+	}
+	return found.clone(), started
+}
+
+// Clock переводит время процесса wall во время блоков, которым считаются окна:
+// время последнего учтённого деплоя плюс прошедшее с его учёта. До первого деплоя возвращает wall.
+func (c *CampaignDetector) Clock(wall time.Time) time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.chainTime.IsZero() {
+		return wall
+	}
+	return c.chainTime.Add(wall.Sub(c.observedAt))
+}
+
+// Expire завершает кампании без деплоев дольше окна и возвращает их итог.
+// now — время блоков (см. Clock).
+func (c *CampaignDetector) Expire(now time.Time) []*Campaign {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var finished []*Campaign
+	for key, entry := range c.keys {
+		if camp := entry.campaign; camp != nil && now.Sub(camp.LastSeen) >= c.window {
+			camp.Finished = true
+			finished = append(finished, camp.clone())
+			entry.campaign = nil
+		}
+		entry.trim(now, c.window)
+		if entry.campaign == nil && len(entry.times) == 0 {
+			delete(c.keys, key)
+		}
+	}
+	return finished
+}
+
+// prune удаляет ключи без деплоев в окне. Вызывается под c.mu.
+func (c *CampaignDetector) prune(now time.Time) {
+	for key, entry := range c.keys {
+		entry.trim(now, c.window)
+		if entry.campaign == nil && len(entry.times) == 0 {
+			delete(c.keys, key)
+		}
+	}
+}
+
+// campaignKeys возвращает признаки деплоя. code_hash — только при заданном списке стандартных
+// и только для кода вне его.
+func (c *CampaignDetector) campaignKeys(meta *Metadata, deployer string) [][2]string {
+	keys := make([][2]string, 0, 4)
+	if hash := strings.ToLower(meta.CodeHash); hash != "" && len(c.standard) > 0 && !c.standard[hash] {
+		keys = append(keys, [2]string{CampaignByCodeHash, hash})
+	}
+	if deployer != "" {
+		keys = append(keys, [2]string{CampaignByDeployer, deployer})
+	}
+	if name := normalizeCampaignText(meta.Name); name != "" {
+		keys = append(keys, [2]string{CampaignByName, name})
+	}
+	if symbol := normalizeCampaignText(meta.Symbol); symbol != "" {
+		keys = append(keys, [2]string{CampaignBySymbol, symbol})
+	}
+	return keys
+}
+
+// normalizeCampaignText приводит name/symbol к виду, в котором копии совпадают.
+func normalizeCampaignText(s string) string {
+	return strings.Join(strings.Fields(strings.ToLower(s)), " ")
+}
+
+// add учитывает деплой в окне. Первые минтеры окна сохраняются для сводки.
+func (e *campaignKey) add(meta *Metadata, now time.Time, window time.Duration) {
+	e.trim(now, window)
+	e.times = append(e.times, now)
+	if len(e.minters) < campaignSampleSize {
+		e.minters = append(e.minters, meta.Address)
+		if meta.Symbol != "" {
+			e.symbols = append(e.symbols, meta.Symbol)
+		}
+	}
+}
+
+// trim отбрасывает деплои старше окна.
+func (e *campaignKey) trim(now time.Time, window time.Duration) {
+	i := 0
+	for i < len(e.times) && now.Sub(e.times[i]) >= window {
+		i++
+	}
+	if i == 0 {
+		return
+	}
+	e.times = e.times[i:]
+	if len(e.times) == 0 {
+		e.minters, e.symbols = nil, nil
+	}
+}
+
+func (c *Campaign) clone() *Campaign {
+	cp := *c
+	cp.Sample = append([]string(nil), c.Sample...)
+	cp.Symbols = append([]string(nil), c.Symbols...)
+	return &cp
+}
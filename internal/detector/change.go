@@ -0,0 +1,31 @@
+package detector
+
+import "time"
+
+// ChangeKind — тип изменения состояния минтера.
+type ChangeKind string
+
+const (
+	ChangeSupply         ChangeKind = "supply_changed"
+	ChangeMintable       ChangeKind = "mintable_changed"
+	ChangeAdminRenounced ChangeKind = "admin_renounced"
+	ChangeAdminChanged   ChangeKind = "admin_changed"
+	ChangeContent        ChangeKind = "content_changed"
+)
+
+// Change описывает изменение, найденное трекером при повторной проверке минтера.
+type Change struct {
+	Kind       ChangeKind
+	Address    string
+	Name       string
+	Symbol     string
+	Old        string
+	New        string
+	DetectedAt time.Time // когда минтер был найден
+	ChangedAt  time.Time // когда изменение замечено
+}
+
+// SinceLaunch возвращает время от обнаружения минтера до изменения.
+func (c *Change) SinceLaunch() time.Duration {
+	return c.ChangedAt.Sub(c.DetectedAt)
+}
@@ -0,0 +1,137 @@
+package detector
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ErrNotMatched возвращается детектором, если контракт ему не подходит.
+var ErrNotMatched = errors.New("контракт не подходит детектору")
+
+// ContractKind — тип обнаруженного контракта.
+type ContractKind string
+
+const (
+	KindJettonMinter  ContractKind = "jetton_minter"
+	KindNFTCollection ContractKind = "nft_collection"
+	KindDexPool       ContractKind = "dex_pool"
+	KindLaunchpad     ContractKind = "launchpad"
+)
+
+// Target описывает задеплоенный контракт, который проверяют детекторы.
+// Interfaces заполняется Fingerprinter для неизвестного кода; nil — fingerprint не снимался.
+type Target struct {
+	Address    string
+	CodeHash   string
+	Code       []byte // BOC кода из StateInit (может отсутствовать)
+	Interfaces InterfaceSet
+}
+
+// Classification — типизированный результат детектора.
+// Заполнено ровно одно из полей Jetton/NFT/Pool/Launchpad в зависимости от Kind.
+type Classification struct {
+	Kind       ContractKind
+	Detector   string
+	Address    string
+	CodeHash   string
+	Timestamp  time.Time
+	Interfaces InterfaceSet
+
+	Jetton    *Metadata
+	NFT       *NFTCollection
+	Pool      *PoolInfo
+	Launchpad *LaunchpadInfo
+}
+
+// ContractDetector распознаёт один класс контрактов.
+// Detect возвращает ErrNotMatched, если контракт не относится к классу.
+type ContractDetector interface {
+	Name() string
+	Detect(ctx context.Context, target Target) (*Classification, error)
+}
+
+// Name реализует ContractDetector для Jetton Minter.
+func (d *Detector) Name() string {
+	return string(KindJettonMinter)
+}
+
+// Detect реализует ContractDetector: верификация по TEP-74 через VerifyAndInspect
+// с учётом уже снятого fingerprint.
+func (d *Detector) Detect(ctx context.Context, target Target) (*Classification, error) {
+	if target.Interfaces != nil && !target.Interfaces.Has("get_jetton_data") && !d.IsKnownCodeHash(target.CodeHash) {
+		return nil, ErrNotMatched
+	}
+
+	meta, err := d.inspect(ctx, target.Address, target.CodeHash, target.Interfaces)
+	if err != nil {
+		if errors.Is(err, ErrNotJettonMinter) {
+			return nil, ErrNotMatched
+		}
+		return nil, err
+	}
+
+	meta.Interfaces = target.Interfaces
+	d.matchCodeFamily(meta, target.Code)
+
+	return &Classification{
+		Kind:       KindJettonMinter,
+		Detector:   d.Name(),
+		Address:    meta.Address,
+		CodeHash:   meta.CodeHash,
+		Timestamp:  meta.Timestamp,
+		Interfaces: target.Interfaces,
+		Jetton:     meta,
+	}, nil
+}
+
+// LaunchpadInfo описывает контракт лаунчпада, найденный по code_hash.
+type LaunchpadInfo struct {
+	Name string
+}
+
+// LaunchpadDetector распознаёт контракты лаунчпадов по списку code_hash из конфига.
+type LaunchpadDetector struct {
+	codeHashes map[string]string // hash -> название лаунчпада
+	logger     *zap.Logger
+}
+
+// NewLaunchpadDetector создаёт детектор лаунчпадов.
+func NewLaunchpadDetector(codeHashes map[string]string, logger *zap.Logger) *LaunchpadDetector {
+	hashes := make(map[string]string, len(codeHashes))
+	for hash, name := range codeHashes {
+		hashes[strings.ToLower(hash)] = name
+	}
+
+	return &LaunchpadDetector{
+		codeHashes: hashes,
+		logger:     logger,
+	}
+}
+
+// Name реализует ContractDetector.
+func (l *LaunchpadDetector) Name() string {
+	return string(KindLaunchpad)
+}
+
+// Detect реализует ContractDetector: достаточно совпадения code_hash.
+func (l *LaunchpadDetector) Detect(_ context.Context, target Target) (*Classification, error) {
+	codeHash := strings.ToLower(target.CodeHash)
+	name, ok := l.codeHashes[codeHash]
+	if !ok {
+		return nil, ErrNotMatched
+	}
+
+	return &Classification{
+		Kind:       KindLaunchpad,
+		Detector:   l.Name(),
+		Address:    target.Address,
+		CodeHash:   codeHash,
+		Timestamp:  time.Now().UTC(),
+		Interfaces: target.Interfaces,
+		Launchpad:  &LaunchpadInfo{Name: name},
+	}, nil
+}
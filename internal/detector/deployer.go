@@ -0,0 +1,205 @@
+package detector
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/yourname/hyper-sniper-indexer/pkg/ton"
+	"go.uber.org/zap"
+)
+
+// Статус деплоера в списках из конфига.
+const (
+	DeployerAllowed = "allowed" // доверенный: уведомление с повышенным приоритетом
+	DeployerBlocked = "blocked" // известный скамер: уведомление подавляется
+)
+
+const (
+	// Минтер считается помеченным от этого risk score (или при подделке защищённого токена)
+	deployerFlagScore = 60
+
+	// Столько деплоев за 24 часа — уже конвейер
+	deployerBurstThreshold = 5
+
+	// Сколько помеченных минтеров отдаём в статистике
+	deployerFlaggedShown = 5
+)
+
+// Deployment — запись истории деплоера о выпущенном минтере.
+type Deployment struct {
+	Minter     string    `json:"minter"`
+	Symbol     string    `json:"symbol,omitempty"`
+	RiskScore  int       `json:"risk_score"`
+	Flagged    bool      `json:"flagged"`
+	DeployedAt time.Time `json:"deployed_at"`
+}
+
+// DeployerStats — репутация деплоера на момент выпуска минтера.
+type DeployerStats struct {
+	Address        string
+	Total          int       // минтеров в истории, включая текущий
+	Last24h        int       // минтеров за последние 24 часа, включая текущий
+	Flagged        int       // ранее выпущенных помеченных минтеров
+	FlaggedMinters []string  // последние помеченные минтеры
+	FirstSeen      time.Time // первый известный деплой
+	List           string    // DeployerAllowed / DeployerBlocked / пусто
+}
+
+// DeployerHistory хранит историю деплоев (реализуется storage.RedisCache).
+// Записи — JSON Deployment, Deployments возвращает их от новых к старым.
+type DeployerHistory interface {
+	AppendDeployment(ctx context.Context, deployer string, payload []byte) error
+	Deployments(ctx context.Context, deployer string) ([][]byte, error)
+}
+
+// DeployerReputation ведёт историю деплоеров и ловит серийных скамеров.
+type DeployerReputation struct {
+	history DeployerHistory
+	lists   map[string]string // raw-адрес -> DeployerAllowed / DeployerBlocked
+	logger  *zap.Logger
+}
+
+// NewDeployerReputation создаёт модуль репутации. Адреса списков — raw или user-friendly;
+// адрес из обоих списков считается заблокированным.
+func NewDeployerReputation(history DeployerHistory, allow, block []string, logger *zap.Logger) *DeployerReputation {
+	lists := make(map[string]string, len(allow)+len(block))
+	add := func(addrs []string, status string) {
+		for _, s := range addrs {
+			addr, err := ton.ParseAddress(s)
+			if err != nil {
+				logger.Warn("некорректный адрес в списке деплоеров",
+					zap.String("address", s),
+					zap.String("list", status),
+				)
+				continue
+			}
+			lists[ton.RawAddress(addr)] = status
+		}
+	}
+	add(allow, DeployerAllowed)
+	add(block, DeployerBlocked)
+
+	return &DeployerReputation{
+		history: history,
+		lists:   lists,
+		logger:  logger,
+	}
+}
+
+// Record дополняет meta статистикой деплоера и причинами риска, затем
+// добавляет минтер в историю. Вызывается после RiskAnalyzer и ImpersonationChecker.
+func (r *DeployerReputation) Record(ctx context.Context, meta *Metadata, deployer string) *DeployerStats {
+	if deployer == "" {
+		return nil
+	}
+	if addr, err := ton.ParseAddress(deployer); err == nil {
+		deployer = ton.RawAddress(addr)
+	}
+
+	now := meta.Timestamp
+	if now.IsZero() {
+		now = time.Now().UTC()
+	}
+
+	stats := &DeployerStats{
+		Address:   deployer,
+		Total:     1,
+		Last24h:   1,
+		FirstSeen: now,
+		List:      r.lists[deployer],
+	}
+	for _, d := range r.deployments(ctx, deployer) {
+		if d.Minter == meta.Address {
+			continue
+		}
+		stats.Total++
+		if now.Sub(d.DeployedAt) < 24*time.Hour {
+			stats.Last24h++
+		}
+		if d.Flagged {
+			stats.Flagged++
+			if len(stats.FlaggedMinters) < deployerFlaggedShown {
+				stats.FlaggedMinters = append(stats.FlaggedMinters, d.Minter)
+			}
+		}
+		if d.DeployedAt.Before(stats.FirstSeen) {
+			stats.FirstSeen = d.DeployedAt
+		}
+	}
+
+	if stats.Flagged > 0 {
+		addRisk(meta, RiskDeployerFlagged)
+	}
+	if stats.Last24h >= deployerBurstThreshold {
+		addRisk(meta, RiskDeployerBurst)
+	}
+	if stats.List == DeployerBlocked {
+		addRisk(meta, RiskDeployerBlocked)
+	}
+	meta.Deployer = stats
+
+	flagged := meta.RiskScore >= deployerFlagScore || meta.Impersonation != nil
+	payload, err := json.Marshal(Deployment{
+		Minter:     meta.Address,
+		Symbol:     meta.Symbol,
+		RiskScore:  meta.RiskScore,
+		Flagged:    flagged,
+		DeployedAt: now,
+	})
+	if err == nil {
+		err = r.history.AppendDeployment(ctx, deployer, payload)
+	}
+	if err != nil {
+		r.logger.Warn("не удалось сохранить деплой в историю",
+			zap.String("deployer", deployer),
+			zap.Error(err),
+		)
+	}
+
+	if stats.Flagged > 0 || stats.List != "" {
+		r.logger.Info("репутация деплоера",
+			zap.String("deployer", deployer),
+			zap.String("minter", meta.Address),
+			zap.Int("total", stats.Total),
+			zap.Int("last_24h", stats.Last24h),
+			zap.Int("flagged", stats.Flagged),
+			zap.String("list", stats.List),
+		)
+	}
+
+	return stats
+}
+
+// deployments читает историю деплоера; битые записи пропускаются.
+func (r *DeployerReputation) deployments(ctx context.Context, deployer string) []Deployment {
+	payloads, err := r.history.Deployments(ctx, deployer)
+	if err != nil {
+		r.logger.Warn("не удалось прочитать историю деплоера",
+			zap.String("deployer", deployer),
+			zap.Error(err),
+		)
+		return nil
+	}
+
+	out := make([]Deployment, 0, len(payloads))
+	for _, payload := range payloads {
+		var d Deployment
+		if err := json.Unmarshal(payload, &d); err != nil {
+			continue
+		}
+		out = append(out, d)
+	}
+	return out
+}
+
+// addRisk добавляет причину риска и пересчитывает score (не больше 100).
+func addRisk(meta *Metadata, reason string) {
+	for _, r := range meta.RiskReasons {
+		if r == reason {
+			return
+		}
+	}
+	meta.RiskReasons = append(meta.RiskReasons, reason)
+	meta.RiskScore = min(meta.RiskScore+riskWeights[reason], 100)
+}
@@ -2,16 +2,31 @@ package detector
 
 import (
 	"context"
+	"encoding/hex"
 	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/xssnick/tonutils-go/address"
+	"github.com/xssnick/tonutils-go/tlb"
+	"github.com/xssnick/tonutils-go/ton/nft"
+	"github.com/xssnick/tonutils-go/tvm/cell"
+	"github.com/yourname/hyper-sniper-indexer/internal/trace"
+	"github.com/yourname/hyper-sniper-indexer/pkg/ton"
 	"go.uber.org/zap"
 )
 
 var (
 	// ErrNotJettonMinter возвращается, если контракт не прошёл верификацию.
 	ErrNotJettonMinter = errors.New("не JettonMinter")
+
+	// ErrTransient возвращается, если проверка не удалась по временной причине
+	// (таймаут, аккаунт ещё не активен) и её стоит повторить позже.
+	ErrTransient = errors.New("временная ошибка проверки")
 )
 
 // Metadata описывает основные поля JettonMinter.
@@ -23,14 +38,47 @@ type Metadata struct {
 	Decimals    int
 	TotalSupply string
 	ContentURI  string // URI метаданных (offchain)
+	ContentHash string // hash ячейки jetton_content (меняется и для on-chain метаданных)
 	AdminAddr   string // адрес админа
 	Mintable    bool   // можно ли минтить ещё
 	Timestamp   time.Time
 	MinterType  string // тип минтера (Official, Stonfi, etc.)
 
 	// Флаги верификации
-	VerifiedByInterface bool // прошёл проверку по get-методам
-	KnownCodeHash       bool // code_hash в whitelist
+	VerifiedByInterface  bool // прошёл проверку по get-методам
+	KnownCodeHash        bool // code_hash в whitelist
+	WalletAddressChecked bool // get_wallet_address вызван и сверен с jetton_wallet_code
+	WalletAddressMatch   bool // адрес кошелька совпал с вычисленным по TEP-74
+	LateVerified         bool // верифицирован повторной проверкой из очереди
+
+	// Get-методы, на которые ответил контракт (только для неизвестного кода)
+	Interfaces InterfaceSet
+
+	// Ближайшее известное семейство кода минтера и сходство с ним (0..1)
+	CodeFamily     string
+	CodeSimilarity float64
+
+	// Защищённый токен, под который маскируется минтер (nil — не похож)
+	Impersonation *ImpersonationMatch
+
+	// Вариант стандарта jetton (Standard*) и его расширения
+	Standard      string
+	Mintless      *MintlessInfo // только для StandardMintless
+	Governed      bool          // минтер отвечает на get_next_admin_address / get_status (stablecoin)
+	NextAdminAddr string        // governed: адрес, которому передаётся админ (пусто — нет)
+
+	// Классификация кода jetton-кошелька (nil, если get_jetton_data не вернул код)
+	WalletCode *WalletCodeInfo
+
+	// Первичный mint из сообщения деплоя (nil — не было)
+	InitialMint *ton.JettonOp
+
+	// История деплоера (заполняется DeployerReputation; nil — деплоер неизвестен)
+	Deployer *DeployerStats
+
+	// Оценка риска (заполняется RiskAnalyzer)
+	RiskScore   int      // 0-100, больше — опаснее
+	RiskReasons []string // коды причин (Risk*)
 
 	// Latency
 	DetectionLatencyMs int64
@@ -43,9 +91,12 @@ type MetadataFetcher interface {
 
 // Detector проверяет code_hash и достаёт метаданные.
 type Detector struct {
-	codeHashes map[string]string // hash -> description
-	fetcher    MetadataFetcher
-	logger     *zap.Logger
+	mu               sync.RWMutex      // защищает codeHashes и walletCodeHashes: их дополняют в runtime
+	codeHashes       map[string]string // hash -> description
+	walletCodeHashes map[string]string // hash jetton_wallet_code -> description
+	families         *CodeFamilies
+	fetcher          MetadataFetcher
+	logger           *zap.Logger
 }
 
 // NewDetector создаёт детектор с заранее известными code_hash.
@@ -56,20 +107,26 @@ func NewDetector(fetcher MetadataFetcher, logger *zap.Logger) *Detector {
 	}
 
 	return &Detector{
-		codeHashes: hashes,
-		fetcher:    fetcher,
-		logger:     logger,
+		codeHashes:       hashes,
+		walletCodeHashes: defaultJettonWalletCodeHashes(),
+		families:         NewCodeFamilies(),
+		fetcher:          fetcher,
+		logger:           logger,
 	}
 }
 
 // IsKnownCodeHash проверяет, есть ли code_hash в whitelist.
 func (d *Detector) IsKnownCodeHash(codeHash string) bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
 	_, ok := d.codeHashes[strings.ToLower(codeHash)]
 	return ok
 }
 
 // GetMinterType возвращает описание типа минтера по code_hash.
 func (d *Detector) GetMinterType(codeHash string) string {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
 	if desc, ok := d.codeHashes[strings.ToLower(codeHash)]; ok {
 		return desc
 	}
@@ -79,7 +136,15 @@ func (d *Detector) GetMinterType(codeHash string) string {
 // VerifyAndInspect проверяет контракт по интерфейсу TEP-74 и извлекает метаданные.
 // КЛЮЧЕВОЙ МЕТОД: Проверяет наличие get_jetton_data и get_wallet_address.
 // Это позволяет обнаруживать ЛЮБЫЕ Jetton Minter, даже с неизвестным code_hash.
+// Адрес из get_wallet_address сверяется с адресом, вычисленным из jetton_wallet_code:
+// результат попадает в WalletAddressMatch и отличает настоящий TEP-74 от подделки.
 func (d *Detector) VerifyAndInspect(ctx context.Context, addr string, codeHash string) (*Metadata, error) {
+	return d.inspect(ctx, addr, codeHash, nil)
+}
+
+// inspect — VerifyAndInspect с результатом fingerprint: get-методы, не ответившие при fingerprint,
+// повторно не вызываются (nil — fingerprint не снимался).
+func (d *Detector) inspect(ctx context.Context, addr string, codeHash string, interfaces InterfaceSet) (*Metadata, error) {
 	startTime := time.Now()
 	codeHashLower := strings.ToLower(codeHash)
 
@@ -90,12 +155,21 @@ func (d *Detector) VerifyAndInspect(ctx context.Context, addr string, codeHash s
 		KnownCodeHash: d.IsKnownCodeHash(codeHashLower),
 		MinterType:    d.GetMinterType(codeHashLower),
 	}
+	if meta.KnownCodeHash {
+		trace.Record(ctx, "code_hash_lookup", startTime, "known: "+meta.MinterType, nil)
+	} else {
+		trace.Record(ctx, "code_hash_lookup", startTime, "unknown", nil)
+	}
 
 	// Если code_hash неизвестен, но fetcher доступен — проверяем по интерфейсу
 	if d.fetcher != nil {
-		verified, jettonData := d.verifyJettonInterface(ctx, addr)
+		verified, jettonData, err := d.verifyJettonInterface(ctx, addr)
 		meta.VerifiedByInterface = verified
 
+		if err != nil && !meta.KnownCodeHash {
+			return nil, fmt.Errorf("%w: %v", ErrTransient, err)
+		}
+
 		if verified {
 			// Заполняем метаданные из get_jetton_data
 			if jettonData != nil {
@@ -103,9 +177,32 @@ func (d *Detector) VerifyAndInspect(ctx context.Context, addr string, codeHash s
 				meta.Mintable = jettonData.Mintable
 				meta.AdminAddr = jettonData.AdminAddr
 				meta.ContentURI = jettonData.ContentURI
+				meta.ContentHash = jettonData.ContentHash
 				meta.Name = jettonData.Name
 				meta.Symbol = jettonData.Symbol
 				meta.Decimals = jettonData.Decimals
+
+				// Вариант стандарта (TEP-74 / mintless TEP-177 / governed) влияет на layout кошелька
+				statusOnly := d.detectStandard(ctx, addr, meta, jettonData, interfaces)
+
+				layout := meta.Standard
+				if statusOnly {
+					layout = StandardGoverned
+				}
+				var governedLayout bool
+				meta.WalletAddressChecked, meta.WalletAddressMatch, governedLayout = d.checkWalletAddress(ctx, addr, jettonData.WalletCode, layout)
+
+				// Форк только с get_status считается governed, если кошелёк построен по layout stablecoin
+				if statusOnly && governedLayout {
+					meta.Governed = true
+					if meta.Standard == StandardTEP74 {
+						meta.Standard = StandardGoverned
+					}
+				}
+
+				if walletCode, err := cell.FromBOC(jettonData.WalletCode); err == nil {
+					meta.WalletCode = d.classifyWalletCode(addr, walletCode)
+				}
 			}
 
 			// Если code_hash неизвестен, но интерфейс прошёл — помечаем как новый тип
@@ -128,20 +225,75 @@ func (d *Detector) VerifyAndInspect(ctx context.Context, addr string, codeHash s
 	return meta, nil
 }
 
+// ReadJettonData перечитывает get_jetton_data уже найденного минтера.
+// Возвращает ErrNotJettonMinter, если ответ не соответствует TEP-74.
+func (d *Detector) ReadJettonData(ctx context.Context, addr string) (*JettonData, error) {
+	verified, data, err := d.verifyJettonInterface(ctx, addr)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrTransient, err)
+	}
+	if !verified || data == nil {
+		return nil, ErrNotJettonMinter
+	}
+	return data, nil
+}
+
+// matchCodeFamily относит код минтера к семейству почти одинакового кода.
+// Фабрики скам-токенов слегка мутируют код, и code_hash каждый раз новый,
+// а структурный отпечаток остаётся близким.
+func (d *Detector) matchCodeFamily(meta *Metadata, codeBOC []byte) {
+	if len(codeBOC) == 0 {
+		return
+	}
+
+	code, err := cell.FromBOC(codeBOC)
+	if err != nil {
+		return
+	}
+
+	family, score := d.families.Observe(meta.CodeHash, familyName(meta), FingerprintCode(code))
+	if family == nil {
+		return
+	}
+
+	meta.CodeFamily = family.Name
+	meta.CodeSimilarity = score
+
+	if !meta.KnownCodeHash && score >= familyThreshold {
+		d.logger.Info("код минтера похож на известное семейство",
+			zap.String("address", meta.Address),
+			zap.String("family", family.Name),
+			zap.Float64("similarity", score),
+		)
+	}
+}
+
+// isTransient отделяет временные ошибки get-метода от ответа «метода нет».
+// Окончательный ответ — только код выхода контракта; таймаут, неактивный аккаунт,
+// сбой liteserver'а или сети стоит повторить.
+func isTransient(err error) bool {
+	return !ton.IsExitCode(err)
+}
+
 // JettonData структура для данных из get_jetton_data.
 type JettonData struct {
 	TotalSupply string
 	Mintable    bool
 	AdminAddr   string
 	ContentURI  string
+	ContentHash string
 	Name        string
 	Symbol      string
 	Decimals    int
+	WalletCode  []byte // BOC jetton_wallet_code
+
+	CustomPayloadAPIURI string // TEP-177: custom_payload_api_uri из on-chain content
 }
 
 // verifyJettonInterface проверяет контракт по интерфейсу TEP-74.
 // Вызывает get_jetton_data и проверяет формат ответа.
-func (d *Detector) verifyJettonInterface(ctx context.Context, addr string) (bool, *JettonData) {
+// Ошибка возвращается только для временных сбоев, которые стоит перепроверить.
+func (d *Detector) verifyJettonInterface(ctx context.Context, addr string) (bool, *JettonData, error) {
 	// Таймаут на проверку интерфейса
 	checkCtx, cancel := context.WithTimeout(ctx, 3*time.Second)
 	defer cancel()
@@ -149,22 +301,28 @@ func (d *Detector) verifyJettonInterface(ctx context.Context, addr string) (bool
 	// 1. Проверяем get_jetton_data
 	// По TEP-74 должен вернуть:
 	// (int total_supply, int mintable, slice admin_address, cell jetton_content, cell jetton_wallet_code)
+	started := time.Now()
 	result, err := d.fetcher.RunGetMethod(checkCtx, addr, "get_jetton_data")
 	if err != nil {
+		trace.Record(ctx, "get_jetton_data", started, "unavailable", err)
 		d.logger.Debug("get_jetton_data не доступен",
 			zap.String("address", addr),
 			zap.Error(err),
 		)
-		return false, nil
+		if isTransient(err) {
+			return false, nil, err
+		}
+		return false, nil, nil
 	}
 
 	// Проверяем, что вернулось хотя бы 4 элемента (минимум для TEP-74)
 	if len(result) < 4 {
+		trace.Record(ctx, "get_jetton_data", started, fmt.Sprintf("stack: %d элементов, нужно 4+", len(result)), nil)
 		d.logger.Debug("get_jetton_data вернул мало данных",
 			zap.String("address", addr),
 			zap.Int("result_len", len(result)),
 		)
-		return false, nil
+		return false, nil, nil
 	}
 
 	// Парсим результат
@@ -189,13 +347,27 @@ func (d *Detector) verifyJettonInterface(ctx context.Context, addr string) (bool
 	// content (четвёртый элемент) — может быть URI или on-chain данные
 	if len(result) > 3 && len(result[3]) > 0 {
 		data.ContentURI = extractContentURI(result[3])
+		if content, err := cell.FromBOC(result[3]); err == nil {
+			data.ContentHash = hex.EncodeToString(content.Hash())
+		} else {
+			trace.Record(ctx, "parse_content", started, "not a cell", err)
+		}
 		// Пытаемся получить name/symbol из content
 		name, symbol, decimals := parseJettonContent(result[3])
 		data.Name = name
 		data.Symbol = symbol
 		data.Decimals = decimals
+		if onchain := parseOnchainContent(result[3]); onchain != nil {
+			data.CustomPayloadAPIURI = onchain.GetAttribute("custom_payload_api_uri")
+		}
+	}
+
+	// jetton_wallet_code (пятый элемент) — нужен для сверки get_wallet_address
+	if len(result) > 4 && len(result[4]) > 0 {
+		data.WalletCode = result[4]
 	}
 
+	trace.Record(ctx, "get_jetton_data", started, "ok", nil)
 	d.logger.Debug("get_jetton_data успешно",
 		zap.String("address", addr),
 		zap.String("total_supply", data.TotalSupply),
@@ -203,17 +375,166 @@ func (d *Detector) verifyJettonInterface(ctx context.Context, addr string) (bool
 		zap.String("admin", data.AdminAddr),
 	)
 
-	return true, data
+	return true, data, nil
+}
+
+// checkWalletAddress вызывает get_wallet_address для пробного владельца и сравнивает
+// ответ с адресом StateInit(jetton_wallet_code, data по layout стандарта standard).
+// Возвращает (проверка выполнена, адреса совпали, совпал layout stablecoin).
+func (d *Detector) checkWalletAddress(ctx context.Context, addr string, walletCodeBOC []byte, standard string) (bool, bool, bool) {
+	if len(walletCodeBOC) == 0 {
+		return false, false, false
+	}
+
+	walletCode, err := cell.FromBOC(walletCodeBOC)
+	if err != nil {
+		d.logger.Debug("jetton_wallet_code не является cell",
+			zap.String("address", addr),
+			zap.Error(err),
+		)
+		return false, false, false
+	}
+
+	minter, err := ton.ParseAddress(addr)
+	if err != nil {
+		return false, false, false
+	}
+
+	checkCtx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	owner := probeOwnerAddress()
+	started := time.Now()
+	result, err := d.fetcher.RunGetMethod(checkCtx, addr, "get_wallet_address",
+		cell.BeginCell().MustStoreAddr(owner).EndCell().BeginParse())
+	if err != nil || len(result) == 0 {
+		trace.Record(ctx, "get_wallet_address", started, "unavailable", err)
+		d.logger.Debug("get_wallet_address не доступен",
+			zap.String("address", addr),
+			zap.Error(err),
+		)
+		return true, false, false
+	}
+
+	reported := parseAddress(result[0])
+	if reported == nil {
+		trace.Record(ctx, "get_wallet_address", started, "not an address", nil)
+		return true, false, false
+	}
+
+	// Stablecoin и mintless хранят в кошельке ещё и status, но некоторые форки оставили layout TEP-74
+	var expected *address.Address
+	layouts := []func(minter, owner *address.Address, code *cell.Cell) *address.Address{CalcJettonWalletAddress}
+	if standard == StandardGoverned || standard == StandardMintless {
+		layouts = []func(minter, owner *address.Address, code *cell.Cell) *address.Address{CalcGovernedWalletAddress, CalcJettonWalletAddress}
+	}
+	match, governed := false, false
+	for i, calc := range layouts {
+		expected = calc(minter, owner, walletCode)
+		if expected.Equals(reported) {
+			match, governed = true, len(layouts) > 1 && i == 0
+			break
+		}
+	}
+
+	// Mintless-кошельки бывают с доп. полями (merkle root, salt) — адрес не вычислить, не считаем проверенным
+	if !match && standard == StandardMintless {
+		trace.Record(ctx, "get_wallet_address", started, "mintless layout unknown", nil)
+		return false, false, false
+	}
+	trace.Record(ctx, "get_wallet_address", started, matchString(match), nil)
+
+	if !match {
+		d.logger.Info("get_wallet_address не совпадает с jetton_wallet_code",
+			zap.String("address", addr),
+			zap.String("reported", reported.String()),
+			zap.String("expected", expected.String()),
+		)
+	}
+
+	return true, match, governed
+}
+
+// WalletAddress возвращает raw-адрес jetton-кошелька владельца: get_wallet_address минтера.
+func (d *Detector) WalletAddress(ctx context.Context, minter, owner string) (string, error) {
+	addr, err := ton.ParseAddress(owner)
+	if err != nil {
+		return "", err
+	}
+	result, err := d.fetcher.RunGetMethod(ctx, minter, "get_wallet_address",
+		cell.BeginCell().MustStoreAddr(addr).EndCell().BeginParse())
+	if err != nil {
+		return "", err
+	}
+	if len(result) == 0 {
+		return "", ErrNotJettonMinter
+	}
+	wallet := parseAddress(result[0])
+	if wallet == nil {
+		return "", ErrNotJettonMinter
+	}
+	return ton.RawAddress(wallet), nil
+}
+
+func matchString(match bool) string {
+	if match {
+		return "match"
+	}
+	return "mismatch"
+}
+
+// CalcJettonWalletAddress вычисляет адрес jetton-кошелька owner для минтера
+// по стандартной раскладке data TEP-74: balance, owner, master, wallet_code.
+func CalcJettonWalletAddress(minter, owner *address.Address, walletCode *cell.Cell) *address.Address {
+	data := cell.BeginCell().
+		MustStoreCoins(0).
+		MustStoreAddr(owner).
+		MustStoreAddr(minter).
+		MustStoreRef(walletCode).
+		EndCell()
+
+	return tlb.StateInit{Code: walletCode, Data: data}.CalcAddress(int(minter.Workchain()))
+}
+
+// CalcGovernedWalletAddress вычисляет адрес кошелька stablecoin-контракта (USDT):
+// data = status:uint4 balance:Coins owner:MsgAddress master:MsgAddress.
+func CalcGovernedWalletAddress(minter, owner *address.Address, walletCode *cell.Cell) *address.Address {
+	data := cell.BeginCell().
+		MustStoreUInt(0, 4).
+		MustStoreCoins(0).
+		MustStoreAddr(owner).
+		MustStoreAddr(minter).
+		EndCell()
+
+	return tlb.StateInit{Code: walletCode, Data: data}.CalcAddress(int(minter.Workchain()))
+}
+
+// probeOwnerAddress возвращает фиксированный адрес владельца для get_wallet_address.
+func probeOwnerAddress() *address.Address {
+	hash := make([]byte, 32)
+	copy(hash, "hypersniper-wallet-probe")
+	return address.NewAddress(0, 0, hash)
+}
+
+// parseAddress извлекает адрес из slice, возвращённого get-методом (BOC).
+func parseAddress(b []byte) *address.Address {
+	c, err := cell.FromBOC(b)
+	if err != nil {
+		return nil
+	}
+	addr, err := c.BeginParse().LoadAddr()
+	if err != nil || addr.Type() != address.StdAddress {
+		return nil
+	}
+	return addr
 }
 
-// bytesToBigIntString конвертирует байты в строку числа.
+// bytesToBigIntString конвертирует big-endian байты в строку числа.
 func bytesToBigIntString(b []byte) string {
 	if len(b) == 0 {
 		return "0"
 	}
-	// Простая конвертация для отображения
-	// В продакшене нужен полноценный BigInt
-	return string(b)
+	return new(big.Int).SetBytes(b).String()
 }
 
 // isZeroBytes проверяет, все ли байты нулевые.
@@ -231,7 +552,13 @@ func parseAddressFromBytes(b []byte) string {
 	if len(b) == 0 {
 		return ""
 	}
-	// Упрощённая версия — в продакшене нужен полный парсинг slice
+	if addr := parseAddress(b); addr != nil {
+		return addr.String()
+	}
+	// addr_none (админ отказался от прав) или неизвестный формат
+	if _, err := cell.FromBOC(b); err == nil {
+		return ""
+	}
 	return string(b)
 }
 
@@ -241,6 +568,20 @@ func extractContentURI(b []byte) string {
 		return ""
 	}
 
+	// Content пришёл как BOC: 0x01 + snake-строка с URI
+	if c, err := cell.FromBOC(b); err == nil {
+		s := c.BeginParse()
+		prefix, err := s.LoadUInt(8)
+		if err != nil || prefix != 0x01 {
+			return ""
+		}
+		uri, err := s.LoadStringSnake()
+		if err != nil {
+			return ""
+		}
+		return strings.TrimSpace(uri)
+	}
+
 	// Первый байт — тип контента
 	// 0x00 = on-chain
 	// 0x01 = off-chain (URI)
@@ -252,14 +593,28 @@ func extractContentURI(b []byte) string {
 	return ""
 }
 
-// parseJettonContent пытается извлечь name/symbol из content.
+// parseJettonContent пытается извлечь name/symbol/decimals из content (TEP-64).
 func parseJettonContent(b []byte) (name, symbol string, decimals int) {
 	if len(b) == 0 {
 		return "", "", 9 // default decimals
 	}
 
-	// Упрощённый парсинг
-	// В продакшене нужен полноценный парсинг Snake format или JSON
+	// TEP-64: on-chain метаданные — словарь sha256(key) -> snake-строка
+	if _, err := cell.FromBOC(b); err == nil {
+		onchain := parseOnchainContent(b)
+		if onchain == nil {
+			// Off-chain: name/symbol лежат в JSON по URI
+			return "", "", 9
+		}
+
+		decimals = 9
+		if d, err := strconv.Atoi(onchain.GetAttribute("decimals")); err == nil {
+			decimals = d
+		}
+		return onchain.GetAttribute("name"), onchain.GetAttribute("symbol"), decimals
+	}
+
+	// Упрощённый парсинг для не-BOC данных
 	content := string(b)
 
 	// Пытаемся найти name и symbol в строке
@@ -273,6 +628,27 @@ func parseJettonContent(b []byte) (name, symbol string, decimals int) {
 	return name, symbol, 9 // default decimals = 9 для TON
 }
 
+// parseOnchainContent разбирает on-chain (или semi-chain) content TEP-64; nil — off-chain или не BOC.
+func parseOnchainContent(b []byte) *nft.ContentOnchain {
+	c, err := cell.FromBOC(b)
+	if err != nil {
+		return nil
+	}
+
+	content, err := nft.ContentFromCell(c)
+	if err != nil {
+		return nil
+	}
+
+	switch v := content.(type) {
+	case *nft.ContentOnchain:
+		return v
+	case *nft.ContentSemichain:
+		return &v.ContentOnchain
+	}
+	return nil
+}
+
 // extractValue извлекает значение поля из строки.
 func extractValue(s, field string) string {
 	// Очень простой парсинг
@@ -340,7 +716,10 @@ func defaultCodeHashes() map[string]string {
 
 // AddCodeHash добавляет новый code_hash в runtime.
 func (d *Detector) AddCodeHash(hash, description string) {
+	d.mu.Lock()
 	d.codeHashes[strings.ToLower(hash)] = description
+	d.mu.Unlock()
+
 	d.logger.Info("добавлен code_hash",
 		zap.String("hash", hash[:16]+"..."),
 		zap.String("description", description),
@@ -349,6 +728,8 @@ func (d *Detector) AddCodeHash(hash, description string) {
 
 // GetKnownHashes возвращает все известные code_hash.
 func (d *Detector) GetKnownHashes() map[string]string {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
 	result := make(map[string]string)
 	for k, v := range d.codeHashes {
 		result[k] = v
@@ -364,7 +745,11 @@ func (d *Detector) LoadRealCodeHashes() {
 	// 2. Известных DEX (Stonfi, DeDust)
 	// 3. Локальной базы проверенных хэшей
 
+	d.mu.RLock()
+	total := len(d.codeHashes)
+	d.mu.RUnlock()
+
 	d.logger.Info("code_hash загружены",
-		zap.Int("total_hashes", len(d.codeHashes)),
+		zap.Int("total_hashes", total),
 	)
 }
@@ -2,65 +2,891 @@ package detector
 
 import (
 	"context"
-	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/xssnick/tonutils-go/address"
+	tonapi "github.com/xssnick/tonutils-go/ton"
+	"github.com/xssnick/tonutils-go/ton/nft"
+	"github.com/xssnick/tonutils-go/tvm/cell"
 	"github.com/yourname/hyper-sniper-indexer/pkg/ton"
 	"go.uber.org/zap"
 )
 
+const testMinter = "0:abababababababababababababababababababababababababababababababab"
+
 type fakeTonClient struct {
-	stack [][]byte
+	stacks map[string][][]byte
 }
 
-func (f *fakeTonClient) Start(context.Context) error                                  { return nil }
-func (f *fakeTonClient) Subscribe(context.Context, ton.Handler) error                 { return nil }
-func (f *fakeTonClient) Catchup(context.Context, time.Time, ton.Handler) error        { return nil }
-func (f *fakeTonClient) RunGetMethod(context.Context, string, string, ...any) ([][]byte, error) {
-	return f.stack, nil
+func (f *fakeTonClient) Start(context.Context) error                           { return nil }
+func (f *fakeTonClient) Subscribe(context.Context, ton.Handler) error          { return nil }
+func (f *fakeTonClient) Catchup(context.Context, time.Time, ton.Handler) error { return nil }
+func (f *fakeTonClient) RunGetMethod(_ context.Context, _ string, method string, _ ...any) ([][]byte, error) {
+	stack, ok := f.stacks[method]
+	if !ok {
+		return nil, tonapi.ContractExecError{Code: 11} // метода нет
+	}
+	return stack, nil
 }
 func (f *fakeTonClient) GetCodeHash(context.Context, string) (string, error) { return "", nil }
 
-func TestIsJettonMinter(t *testing.T) {
-	logger := zap.NewNop()
-	d := NewDetector(&fakeTonClient{}, logger)
+func testWalletCode() *cell.Cell {
+	return cell.BeginCell().MustStoreUInt(0xdeadbeef, 32).EndCell()
+}
+
+func jettonDataStack(walletCode *cell.Cell) [][]byte {
+	admin := address.NewAddress(0, 0, make([]byte, 32))
+	content := cell.BeginCell().
+		MustStoreUInt(0x01, 8).
+		MustStoreStringSnake("https://example.com/jetton.json").
+		EndCell()
+
+	return [][]byte{
+		big.NewInt(1_000_000_000).Bytes(),
+		big.NewInt(1).Bytes(),
+		cell.BeginCell().MustStoreAddr(admin).EndCell().ToBOC(),
+		content.ToBOC(),
+		walletCode.ToBOC(),
+	}
+}
+
+func addrStack(addr *address.Address) [][]byte {
+	return [][]byte{cell.BeginCell().MustStoreAddr(addr).EndCell().ToBOC()}
+}
 
-	if !d.IsJettonMinter("6d9f5c5d5e6f7a8b9c0d1e2f3a4b5c6d7e8f9a0b1c2d3e4f5a6b7c8d9e0f1a2b") {
+func TestIsKnownCodeHash(t *testing.T) {
+	d := NewDetector(&fakeTonClient{}, zap.NewNop())
+
+	if !d.IsKnownCodeHash("USDT_TON_MINTER") {
 		t.Fatalf("expected hash to be recognized")
 	}
 
-	if d.IsJettonMinter("deadbeef") {
+	if d.IsKnownCodeHash("deadbeef") {
 		t.Fatalf("unexpected hash accepted")
 	}
 }
 
-func TestInspectParsesMetadata(t *testing.T) {
-	logger := zap.NewNop()
+func TestVerifyAndInspectParsesJettonData(t *testing.T) {
+	code := testWalletCode()
+	minter, _ := ton.ParseAddress(testMinter)
+
+	fake := &fakeTonClient{stacks: map[string][][]byte{
+		"get_jetton_data":    jettonDataStack(code),
+		"get_wallet_address": addrStack(CalcJettonWalletAddress(minter, probeOwnerAddress(), code)),
+	}}
 
-	dec := make([]byte, 8)
-	binary.BigEndian.PutUint64(dec, 9)
+	d := NewDetector(fake, zap.NewNop())
+
+	meta, err := d.VerifyAndInspect(context.Background(), testMinter, "deadbeef")
+	if err != nil {
+		t.Fatalf("verify returned error: %v", err)
+	}
 
-	fake := &fakeTonClient{
-		stack: [][]byte{
-			[]byte("TestName"),
-			[]byte("TST"),
-			dec,
+	if !meta.VerifiedByInterface || meta.KnownCodeHash {
+		t.Fatalf("unexpected verification flags: %+v", meta)
+	}
+	if meta.TotalSupply != "1000000000" || !meta.Mintable {
+		t.Fatalf("unexpected supply data: %+v", meta)
+	}
+	if meta.ContentURI != "https://example.com/jetton.json" {
+		t.Fatalf("unexpected content uri: %q", meta.ContentURI)
+	}
+	if !meta.WalletAddressChecked || !meta.WalletAddressMatch {
+		t.Fatalf("wallet address should match: %+v", meta)
+	}
+}
+
+func TestVerifyAndInspectDetectsWalletMismatch(t *testing.T) {
+	fake := &fakeTonClient{stacks: map[string][][]byte{
+		"get_jetton_data":    jettonDataStack(testWalletCode()),
+		"get_wallet_address": addrStack(address.NewAddress(0, 0, make([]byte, 32))),
+	}}
+
+	d := NewDetector(fake, zap.NewNop())
+
+	meta, err := d.VerifyAndInspect(context.Background(), testMinter, "deadbeef")
+	if err != nil {
+		t.Fatalf("verify returned error: %v", err)
+	}
+
+	if !meta.WalletAddressChecked || meta.WalletAddressMatch {
+		t.Fatalf("wallet address mismatch not reported: %+v", meta)
+	}
+}
+
+func TestVerifyAndInspectRejectsNonJetton(t *testing.T) {
+	d := NewDetector(&fakeTonClient{}, zap.NewNop())
+
+	if _, err := d.VerifyAndInspect(context.Background(), testMinter, "deadbeef"); !errors.Is(err, ErrNotJettonMinter) {
+		t.Fatalf("expected ErrNotJettonMinter, got %v", err)
+	}
+}
+
+type fakeAccountClient struct {
+	fakeTonClient
+	codeHashes map[string]string
+	codeErr    error // ошибка GetCodeHash вместо «аккаунт не активен»
+}
+
+func (f *fakeAccountClient) GetCodeHash(_ context.Context, addr string) (string, error) {
+	if f.codeErr != nil {
+		return "", f.codeErr
+	}
+	hash, ok := f.codeHashes[addr]
+	if !ok {
+		return "", fmt.Errorf("account not active: %w", ton.ErrNotReady)
+	}
+	return hash, nil
+}
+
+func TestRiskAnalyzerScoresReasons(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	admin := "EQ_admin"
+	client := &fakeAccountClient{
+		fakeTonClient: fakeTonClient{stacks: map[string][][]byte{}},
+		codeHashes:    map[string]string{admin: "84dafa449f98a6987789ba232358072bc0f76dc4524002a5d0918b9a75d2d599"},
+	}
+
+	meta := &Metadata{
+		Address:    testMinter,
+		Mintable:   true,
+		AdminAddr:  admin,
+		ContentURI: srv.URL + "/meta.json",
+		WalletCode: &WalletCodeInfo{Hash: "ffff", Checked: true, Family: "Unknown"},
+	}
+
+	NewRiskAnalyzer(client, zap.NewNop()).Assess(context.Background(), meta, "")
+
+	want := []string{
+		RiskMintableWithAdmin,
+		RiskAdminIsWallet,
+		RiskUnknownWalletCode,
+		RiskNonStandardMinter,
+		RiskMetadataUnreachable,
+	}
+	if !reflect.DeepEqual(meta.RiskReasons, want) {
+		t.Fatalf("unexpected reasons: %v", meta.RiskReasons)
+	}
+	if meta.RiskScore != 75 {
+		t.Fatalf("unexpected score: %d", meta.RiskScore)
+	}
+}
+
+func TestRiskAnalyzerMetadataPublicHostsAndCache(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		hits.Add(1)
+		w.Write([]byte(`{"name":"Token","symbol":"TKN"}`))
+	}))
+	defer srv.Close()
+
+	client := &fakeAccountClient{fakeTonClient: fakeTonClient{stacks: map[string][][]byte{}}}
+	uri := srv.URL + "/meta.json"
+
+	// Сервер на 127.0.0.1 — URI деплоера не может вести на внутренние хосты
+	analyzer := NewRiskAnalyzer(client, zap.NewNop())
+	meta := &Metadata{Address: testMinter, KnownCodeHash: true, ContentURI: uri}
+	analyzer.Assess(context.Background(), meta, "")
+	if hits.Load() != 0 || meta.Name != "" || !reflect.DeepEqual(meta.RiskReasons, []string{RiskMetadataUnreachable}) {
+		t.Fatalf("loopback URI fetched: hits=%d meta=%+v", hits.Load(), meta)
+	}
+
+	// Повторные деплои с тем же URI берут результат из кэша
+	analyzer = NewRiskAnalyzer(client, zap.NewNop())
+	analyzer.httpClient = srv.Client()
+	for i := 0; i < 3; i++ {
+		meta := &Metadata{Address: testMinter, KnownCodeHash: true, ContentURI: uri}
+		analyzer.Assess(context.Background(), meta, "")
+		if meta.Name != "Token" || meta.Symbol != "TKN" || len(meta.RiskReasons) != 0 {
+			t.Fatalf("unexpected metadata: %+v", meta)
+		}
+	}
+	if hits.Load() != 1 {
+		t.Fatalf("metadata URI fetched %d times", hits.Load())
+	}
+}
+
+func TestIsPublicIP(t *testing.T) {
+	for _, addr := range []string{"127.0.0.1", "10.1.2.3", "192.168.0.1", "169.254.169.254", "100.64.1.1", "0.0.0.0", "::1", "fe80::1", "fd00::1"} {
+		if isPublicIP(net.ParseIP(addr)) {
+			t.Fatalf("%s must not be public", addr)
+		}
+	}
+	for _, addr := range []string{"1.1.1.1", "2606:4700::1111"} {
+		if !isPublicIP(net.ParseIP(addr)) {
+			t.Fatalf("%s must be public", addr)
+		}
+	}
+}
+
+func TestRiskAnalyzerAdminUnknownOnTransientError(t *testing.T) {
+	analyzer := NewRiskAnalyzer(&fakeAccountClient{fakeTonClient: fakeTonClient{stacks: map[string][][]byte{}}}, zap.NewNop())
+	if got := analyzer.classifyAdmin(context.Background(), "EQ_fresh"); got != RiskAdminIsWallet {
+		t.Fatalf("inactive admin should be a wallet, got %q", got)
+	}
+
+	// Таймаут liteserver'а — тип админа неизвестен
+	analyzer.fetcher = &fakeAccountClient{codeErr: fmt.Errorf("lookup: %w", context.DeadlineExceeded)}
+	if got := analyzer.classifyAdmin(context.Background(), "EQ_admin"); got != "" {
+		t.Fatalf("transient error classified as %q", got)
+	}
+}
+
+func TestRiskAnalyzerDeployerSupply(t *testing.T) {
+	deployer := address.NewAddress(0, 0, make([]byte, 32))
+	wallet := address.NewAddress(0, 0, []byte("wallet-wallet-wallet-wallet-1234"))
+
+	client := &fakeAccountClient{fakeTonClient: fakeTonClient{stacks: map[string][][]byte{
+		"get_wallet_address": addrStack(wallet),
+		"get_wallet_data":    {big.NewInt(900).Bytes()},
+	}}}
+
+	meta := &Metadata{
+		Address:       testMinter,
+		Name:          "Token",
+		KnownCodeHash: true,
+		TotalSupply:   "1000",
+	}
+
+	NewRiskAnalyzer(client, zap.NewNop()).Assess(context.Background(), meta, ton.RawAddress(deployer))
+
+	if !reflect.DeepEqual(meta.RiskReasons, []string{RiskDeployerHoldsSupply}) {
+		t.Fatalf("unexpected reasons: %v", meta.RiskReasons)
+	}
+}
+
+// pinnedWalletClient не находит кошелёк деплоера на блоке деплоя: его ещё нет до первичного mint.
+type pinnedWalletClient struct {
+	fakeAccountClient
+}
+
+func (f *pinnedWalletClient) RunGetMethod(ctx context.Context, addr string, method string, args ...any) ([][]byte, error) {
+	if ref, ok := ton.BlockFromContext(ctx); ok && ref.Seqno != 0 && method == "get_wallet_data" {
+		return nil, fmt.Errorf("account not active: %w", ton.ErrNotReady)
+	}
+	return f.fakeAccountClient.RunGetMethod(ctx, addr, method, args...)
+}
+
+func TestRiskAnalyzerDeployerSupplyAtLatestBlock(t *testing.T) {
+	deployer := address.NewAddress(0, 0, make([]byte, 32))
+	wallet := address.NewAddress(0, 0, []byte("wallet-wallet-wallet-wallet-1234"))
+
+	client := &pinnedWalletClient{fakeAccountClient{fakeTonClient: fakeTonClient{stacks: map[string][][]byte{
+		"get_wallet_address": addrStack(wallet),
+		"get_wallet_data":    {big.NewInt(900).Bytes()},
+	}}}}
+
+	meta := &Metadata{
+		Address:       testMinter,
+		Name:          "Token",
+		KnownCodeHash: true,
+		TotalSupply:   "1000",
+	}
+
+	ctx := ton.WithBlock(context.Background(), ton.BlockRef{Seqno: 42})
+	NewRiskAnalyzer(client, zap.NewNop()).Assess(ctx, meta, ton.RawAddress(deployer))
+
+	if !reflect.DeepEqual(meta.RiskReasons, []string{RiskDeployerHoldsSupply}) {
+		t.Fatalf("unexpected reasons: %v", meta.RiskReasons)
+	}
+}
+
+func TestRiskAnalyzerDeployerSupplyFromInitialMint(t *testing.T) {
+	deployer := address.NewAddress(0, 0, make([]byte, 32))
+
+	// Get-методов кошелька нет: долю видно только по первичному mint
+	client := &fakeAccountClient{fakeTonClient: fakeTonClient{stacks: map[string][][]byte{}}}
+
+	meta := &Metadata{
+		Address:       testMinter,
+		Name:          "Token",
+		KnownCodeHash: true,
+		TotalSupply:   "1000",
+		InitialMint: &ton.JettonOp{
+			Kind:        ton.OpMint,
+			Destination: deployer.String(),
+			Amount:      "900",
 		},
 	}
 
-	d := NewDetector(fake, logger)
+	NewRiskAnalyzer(client, zap.NewNop()).Assess(context.Background(), meta, ton.RawAddress(deployer))
+
+	if !reflect.DeepEqual(meta.RiskReasons, []string{RiskDeployerHoldsSupply}) {
+		t.Fatalf("unexpected reasons: %v", meta.RiskReasons)
+	}
+
+	// Mint другому адресу не считается
+	meta.RiskReasons, meta.RiskScore = nil, 0
+	meta.InitialMint.Destination = address.NewAddress(0, 0, []byte("someone-else-someone-else-123456")).String()
+	NewRiskAnalyzer(client, zap.NewNop()).Assess(context.Background(), meta, ton.RawAddress(deployer))
+
+	if len(meta.RiskReasons) != 0 {
+		t.Fatalf("unexpected reasons: %v", meta.RiskReasons)
+	}
+}
+
+func TestInspectWalletCodeFindsPatterns(t *testing.T) {
+	standard := cell.BeginCell().MustStoreSlice([]byte{0xC7, 0x05, 0xF2, 0x40}, 32).EndCell()
+	if got := inspectWalletCode(standard); len(got) != 0 {
+		t.Fatalf("standard code flagged: %v", got)
+	}
+
+	// Blacklist в отдельной ячейке + проверка бита паузы + NOW
+	blacklist := cell.BeginCell().MustStoreSlice([]byte{0xF4, 0x0E}, 16).EndCell()
+	suspicious := cell.BeginCell().
+		MustStoreSlice([]byte{0xB0, 0xF2, 0x44, 0xF8, 0x23}, 40).
+		MustStoreRef(blacklist).
+		EndCell()
+
+	want := []string{WalletPatternDictLookup, WalletPatternPauseFlag, WalletPatternTimeCheck}
+	if got := inspectWalletCode(suspicious); !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected patterns: %v", got)
+	}
+}
+
+func TestClassifyWalletCodeKnown(t *testing.T) {
+	d := NewDetector(&fakeTonClient{}, zap.NewNop())
+	code := testWalletCode()
+
+	// Пустой каталог: сверять не с чем, риск unknown_wallet_code не добавляется
+	info := d.classifyWalletCode(testMinter, code)
+	if info.Checked || info.Known {
+		t.Fatalf("code checked against empty catalog: %+v", info)
+	}
+	meta := &Metadata{Address: testMinter, Name: "Token", KnownCodeHash: true, WalletCode: info}
+	NewRiskAnalyzer(&fakeAccountClient{fakeTonClient: fakeTonClient{stacks: map[string][][]byte{}}}, zap.NewNop()).Assess(context.Background(), meta, "")
+	if len(meta.RiskReasons) != 0 {
+		t.Fatalf("unexpected reasons with empty catalog: %v", meta.RiskReasons)
+	}
+
+	d.AddWalletCodeHash(hex.EncodeToString(code.Hash()), "Test Wallet")
+	info = d.classifyWalletCode(testMinter, code)
+	if !info.Checked || !info.Known || info.Family != "Test Wallet" || info.Suspicious != nil {
+		t.Fatalf("unexpected classification: %+v", info)
+	}
+}
+
+func TestPoolDetectorDeDust(t *testing.T) {
+	jetton := address.NewAddress(0, 0, []byte("jetton-jetton-jetton-jetton-1234"))
+	native := cell.BeginCell().MustStoreUInt(0, 4).EndCell()
+	asset := cell.BeginCell().MustStoreUInt(1, 4).MustStoreInt(0, 8).MustStoreSlice(jetton.Data(), 256).EndCell()
+
+	fake := &fakeTonClient{stacks: map[string][][]byte{
+		"get_assets":   {native.ToBOC(), asset.ToBOC()},
+		"get_reserves": {big.NewInt(100).Bytes(), big.NewInt(2500).Bytes()},
+	}}
+
+	cls, err := NewPoolDetector(fake, zap.NewNop()).Detect(context.Background(), Target{Address: testMinter})
+	if err != nil {
+		t.Fatalf("detect returned error: %v", err)
+	}
+
+	want := &PoolInfo{DEX: DexDeDust, Token0: NativeTON, Token1: ton.RawAddress(jetton), Reserve0: "100", Reserve1: "2500"}
+	if cls.Kind != KindDexPool || !reflect.DeepEqual(cls.Pool, want) {
+		t.Fatalf("unexpected pool: %+v", cls.Pool)
+	}
+}
+
+type countingClient struct {
+	fakeTonClient
+	calls atomic.Int64
+}
+
+func (c *countingClient) RunGetMethod(ctx context.Context, addr, method string, args ...any) ([][]byte, error) {
+	c.calls.Add(1)
+	return c.fakeTonClient.RunGetMethod(ctx, addr, method, args...)
+}
+
+func TestFingerprintCachesByCodeHash(t *testing.T) {
+	client := &countingClient{fakeTonClient: fakeTonClient{stacks: map[string][][]byte{
+		"seqno":          {big.NewInt(1).Bytes()},
+		"get_public_key": {big.NewInt(42).Bytes()},
+	}}}
+
+	f := NewFingerprinter(client, []string{"seqno", "get_public_key", "get_jetton_data"}, time.Second, zap.NewNop())
+
+	set := f.Fingerprint(context.Background(), Target{Address: testMinter, CodeHash: "ABCD"})
+	if !reflect.DeepEqual(set, InterfaceSet{"get_public_key", "seqno"}) {
+		t.Fatalf("unexpected interfaces: %v", set)
+	}
+	if !set.Has("seqno") || set.Has("get_jetton_data") {
+		t.Fatalf("Has returned wrong result for %v", set)
+	}
+
+	again := f.Fingerprint(context.Background(), Target{Address: "0:other", CodeHash: "abcd"})
+	if !reflect.DeepEqual(again, set) || client.calls.Load() != 3 {
+		t.Fatalf("code hash probed twice: calls=%d", client.calls.Load())
+	}
+}
+
+// flakyClient отвечает сетевой ошибкой на первые failures вызовов метода flaky.
+type flakyClient struct {
+	countingClient
+	flaky    string
+	failures atomic.Int64
+}
+
+func (c *flakyClient) RunGetMethod(ctx context.Context, addr, method string, args ...any) ([][]byte, error) {
+	if method == c.flaky && c.failures.Add(-1) >= 0 {
+		c.calls.Add(1)
+		return nil, errors.New("liteserver: connection reset")
+	}
+	return c.countingClient.RunGetMethod(ctx, addr, method, args...)
+}
+
+func TestFingerprintRetriesNonExitErrors(t *testing.T) {
+	client := &flakyClient{
+		countingClient: countingClient{fakeTonClient: fakeTonClient{stacks: map[string][][]byte{
+			"get_jetton_data": jettonDataStack(testWalletCode()),
+		}}},
+		flaky: "get_jetton_data",
+	}
+	client.failures.Store(1)
+	f := NewFingerprinter(client, []string{"get_jetton_data", "seqno"}, time.Second, zap.NewNop())
+
+	// Сбой liteserver'а — не ответ «метода нет»: результат не кэшируется
+	if set := f.Fingerprint(context.Background(), Target{Address: testMinter, CodeHash: "ef01"}); set != nil {
+		t.Fatalf("incomplete fingerprint returned: %v", set)
+	}
+	if _, ok := f.Lookup("ef01"); ok {
+		t.Fatalf("incomplete fingerprint cached")
+	}
 
-	meta, err := d.Inspect(context.Background(), "addr", "6d9f5c5d5e6f7a8b9c0d1e2f3a4b5c6d7e8f9a0b1c2d3e4f5a6b7c8d9e0f1a2b")
+	set := f.Fingerprint(context.Background(), Target{Address: testMinter, CodeHash: "ef01"})
+	if !reflect.DeepEqual(set, InterfaceSet{"get_jetton_data"}) {
+		t.Fatalf("unexpected interfaces after retry: %v", set)
+	}
+	if cached, ok := f.Lookup("ef01"); !ok || !reflect.DeepEqual(cached, set) {
+		t.Fatalf("complete fingerprint not cached: %v", cached)
+	}
+}
+
+func TestLRUCacheEvictsOldest(t *testing.T) {
+	c := newLRUCache[string, int](2)
+	c.Add("a", 1)
+	c.Add("b", 2)
+	c.Get("a") // a свежее b
+	c.Add("c", 3)
+
+	if _, ok := c.Get("b"); ok {
+		t.Fatalf("least recently used entry not evicted")
+	}
+	if v, ok := c.Get("a"); !ok || v != 1 || c.Len() != 2 {
+		t.Fatalf("unexpected cache state: a=%d ok=%v len=%d", v, ok, c.Len())
+	}
+}
+
+// testMinterCode строит дерево кода из нескольких ячеек, в одной из которых константа.
+func testMinterCode(constant byte) *cell.Cell {
+	body := make([]byte, 120)
+	for i := range body {
+		body[i] = byte(i*7 + 3)
+	}
+
+	root := cell.BeginCell().MustStoreSlice(body[:60], 480)
+	for i := 0; i < 3; i++ {
+		chunk := append([]byte{byte(i)}, body[60+i*20:80+i*20]...)
+		root.MustStoreRef(cell.BeginCell().MustStoreSlice(chunk, uint(len(chunk)*8)).EndCell())
+	}
+	root.MustStoreRef(cell.BeginCell().MustStoreSlice([]byte{0x80, constant, 0xF2}, 24).EndCell())
+	return root.EndCell()
+}
+
+func TestCodeFamiliesClusterMutatedCode(t *testing.T) {
+	original, mutated := testMinterCode(0x10), testMinterCode(0x11)
+	if string(original.Hash()) == string(mutated.Hash()) {
+		t.Fatal("mutation must change code hash")
+	}
+
+	fpOriginal, fpMutated := FingerprintCode(original), FingerprintCode(mutated)
+	if score := fpOriginal.Similarity(fpMutated); score < familyThreshold {
+		t.Fatalf("mutated code similarity too low: %.2f", score)
+	}
+
+	other := FingerprintCode(cell.BeginCell().MustStoreSlice([]byte("completely different contract"), 232).EndCell())
+	if score := fpOriginal.Similarity(other); score >= 0.3 {
+		t.Fatalf("unrelated code similarity too high: %.2f", score)
+	}
+
+	families := NewCodeFamilies()
+	if family, _ := families.Observe("AA", "family-aa", fpOriginal); family != nil {
+		t.Fatalf("first code must not have a nearest family, got %s", family.Name)
+	}
+
+	family, score := families.Observe("bb", "family-bb", fpMutated)
+	if family == nil || family.Name != "family-aa" || score < familyThreshold {
+		t.Fatalf("mutated code not clustered: %+v %.2f", family, score)
+	}
+	if len(family.CodeHashes) != 2 {
+		t.Fatalf("unexpected family members: %v", family.CodeHashes)
+	}
+
+	families.Observe("cc", "family-cc", other)
+	if families.Len() != 2 {
+		t.Fatalf("expected 2 families, got %d", families.Len())
+	}
+}
+
+func TestDetectorCodeHashesConcurrentAccess(t *testing.T) {
+	d := NewDetector(nil, zap.NewNop())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			d.AddCodeHash(fmt.Sprintf("%064x", i), "runtime")
+			d.AddWalletCodeHash(fmt.Sprintf("%064x", i), "runtime")
+		}(i)
+		go func(i int) {
+			defer wg.Done()
+			d.IsKnownCodeHash(fmt.Sprintf("%064x", i))
+			d.GetMinterType(fmt.Sprintf("%064x", i))
+			_ = d.GetKnownHashes()
+		}(i)
+	}
+	wg.Wait()
+
+	if !d.IsKnownCodeHash(fmt.Sprintf("%064x", 7)) {
+		t.Fatal("runtime code hash lost")
+	}
+}
+
+func TestCodeFamiliesBoundedAndAtomic(t *testing.T) {
+	fingerprint := func(i int) *CodeFingerprint {
+		fp := &CodeFingerprint{}
+		for j := range fp.MinHash {
+			fp.MinHash[j] = uint64(i)<<8 | uint64(j)
+		}
+		return fp
+	}
+
+	families := NewCodeFamilies()
+	for i := 0; i < maxCodeFamilies; i++ {
+		families.Observe(fmt.Sprintf("h%d", i), fmt.Sprintf("f%d", i), fingerprint(i))
+		// h0 остаётся недавним, вытесняться должно h1
+		families.Observe("h0", "f0", fingerprint(0))
+	}
+	families.Observe("overflow", "f-overflow", fingerprint(maxCodeFamilies))
+
+	if families.Len() != maxCodeFamilies {
+		t.Fatalf("expected %d families, got %d", maxCodeFamilies, families.Len())
+	}
+	if family, _ := families.Nearest(fingerprint(0)); family == nil || family.Name != "f0" {
+		t.Fatalf("recently used family evicted: %+v", family)
+	}
+	if family, score := families.Nearest(fingerprint(1)); family != nil && score >= familyThreshold {
+		t.Fatalf("least recently used family kept: %s", family.Name)
+	}
+
+	// Параллельные деплои одного нового кода дают одно семейство
+	families = NewCodeFamilies()
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			families.Observe(fmt.Sprintf("dup%d", i), fmt.Sprintf("dup-%d", i), fingerprint(7))
+		}(i)
+	}
+	wg.Wait()
+	if families.Len() != 1 {
+		t.Fatalf("concurrent observe created %d families", families.Len())
+	}
+}
+
+func TestImpersonationChecker(t *testing.T) {
+	checker := NewImpersonationChecker(DefaultProtectedTokens(), zap.NewNop())
+
+	cases := []struct {
+		name, symbol string
+		method       string
+	}{
+		{"Tether", "USDT", MatchExact},
+		{"", "UЅDТ", MatchHomoglyph}, // кириллические Ѕ и Т
+		{"", "U$D₮", MatchHomoglyph},
+		{"", "USTD", MatchEditDistance},
+		{"Tether USD Official", "TUSD", MatchContains},
+		{"Not Coin", "N0T", MatchHomoglyph},
+		{"Hot Dog", "HOT", ""},
+	}
+
+	for _, tc := range cases {
+		meta := &Metadata{Address: testMinter, Name: tc.name, Symbol: tc.symbol}
+		match := checker.Check(meta)
+		if tc.method == "" {
+			if match != nil {
+				t.Fatalf("%s/%s: unexpected match %+v", tc.name, tc.symbol, match)
+			}
+			continue
+		}
+		if match == nil || match.Method != tc.method || meta.Impersonation != match {
+			t.Fatalf("%s/%s: expected %s, got %+v", tc.name, tc.symbol, tc.method, match)
+		}
+	}
+
+	// Настоящий минтер USDT подделкой не считается
+	real := &Metadata{Address: "EQCxE6mUtQJKFnGfaROTKOt1lZbDiiX1kCixRv7Nw2Id_sDs", Name: "Tether USD", Symbol: "USDT"}
+	if match := checker.Check(real); match != nil {
+		t.Fatalf("canonical minter flagged: %+v", match)
+	}
+}
+
+func TestVerifyAndInspectDetectsMintless(t *testing.T) {
+	code := testWalletCode()
+	minter, _ := ton.ParseAddress(testMinter)
+
+	content := &nft.ContentOnchain{}
+	if err := content.SetAttribute("symbol", "DROP"); err != nil {
+		t.Fatal(err)
+	}
+	if err := content.SetAttribute("custom_payload_api_uri", "https://api.example.com/claim"); err != nil {
+		t.Fatal(err)
+	}
+	contentCell, err := content.ContentCell()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stack := jettonDataStack(code)
+	stack[3] = contentCell.ToBOC()
+
+	fake := &fakeTonClient{stacks: map[string][][]byte{
+		"get_jetton_data":                   stack,
+		"get_wallet_address":                addrStack(CalcGovernedWalletAddress(minter, probeOwnerAddress(), code)),
+		"get_next_admin_address":            {cell.BeginCell().MustStoreUInt(0, 2).EndCell().ToBOC()},
+		"get_mintless_airdrop_hashmap_root": {{0xab, 0xcd}},
+	}}
+
+	meta, err := NewDetector(fake, zap.NewNop()).VerifyAndInspect(context.Background(), testMinter, "deadbeef")
+	if err != nil {
+		t.Fatalf("verify returned error: %v", err)
+	}
+
+	if meta.Standard != StandardMintless || !meta.Governed || meta.Symbol != "DROP" {
+		t.Fatalf("unexpected standard: %+v", meta)
+	}
+	if meta.Mintless.CustomPayloadAPIURI != "https://api.example.com/claim" ||
+		meta.Mintless.MerkleRoot != strings.Repeat("00", 30)+"abcd" {
+		t.Fatalf("unexpected mintless fields: %+v", meta.Mintless)
+	}
+	if !meta.WalletAddressChecked || !meta.WalletAddressMatch {
+		t.Fatalf("governed wallet layout should match: %+v", meta)
+	}
+}
+
+func TestDetectStandardStatusOnlyNeedsGovernedLayout(t *testing.T) {
+	code := testWalletCode()
+	minter, _ := ton.ParseAddress(testMinter)
+	status := [][]byte{big.NewInt(0).Bytes()}
+
+	// get_status без get_next_admin_address и кошелёк по layout TEP-74: не governed
+	plain := &fakeTonClient{stacks: map[string][][]byte{
+		"get_jetton_data":    jettonDataStack(code),
+		"get_wallet_address": addrStack(CalcJettonWalletAddress(minter, probeOwnerAddress(), code)),
+		"get_status":         status,
+	}}
+	meta, err := NewDetector(plain, zap.NewNop()).VerifyAndInspect(context.Background(), testMinter, "deadbeef")
+	if err != nil {
+		t.Fatalf("verify returned error: %v", err)
+	}
+	if meta.Governed || meta.Standard != StandardTEP74 || !meta.WalletAddressMatch {
+		t.Fatalf("get_status alone must not mark governed: %+v", meta)
+	}
+
+	// Тот же get_status и кошелёк по layout stablecoin: governed
+	governed := &fakeTonClient{stacks: map[string][][]byte{
+		"get_jetton_data":    jettonDataStack(code),
+		"get_wallet_address": addrStack(CalcGovernedWalletAddress(minter, probeOwnerAddress(), code)),
+		"get_status":         status,
+	}}
+	meta, err = NewDetector(governed, zap.NewNop()).VerifyAndInspect(context.Background(), testMinter, "deadbeef")
+	if err != nil {
+		t.Fatalf("verify returned error: %v", err)
+	}
+	if !meta.Governed || meta.Standard != StandardGoverned || !meta.WalletAddressMatch {
+		t.Fatalf("governed wallet layout must mark governed: %+v", meta)
+	}
+}
+
+func TestDetectSkipsMethodsMissingFromFingerprint(t *testing.T) {
+	code := testWalletCode()
+	minter, _ := ton.ParseAddress(testMinter)
+	client := &countingClient{fakeTonClient: fakeTonClient{stacks: map[string][][]byte{
+		"get_jetton_data":    jettonDataStack(code),
+		"get_wallet_address": addrStack(CalcJettonWalletAddress(minter, probeOwnerAddress(), code)),
+	}}}
+	d := NewDetector(client, zap.NewNop())
+
+	target := Target{Address: testMinter, CodeHash: "deadbeef", Interfaces: InterfaceSet{"get_jetton_data", "get_wallet_address"}}
+	cls, err := d.Detect(context.Background(), target)
 	if err != nil {
-		t.Fatalf("inspect returned error: %v", err)
+		t.Fatalf("detect returned error: %v", err)
+	}
+	if cls.Jetton.Standard != StandardTEP74 || !cls.Jetton.WalletAddressMatch {
+		t.Fatalf("unexpected jetton: %+v", cls.Jetton)
+	}
+	// Только get_jetton_data и get_wallet_address: расширения стандарта fingerprint уже исключил
+	if calls := client.calls.Load(); calls != 2 {
+		t.Fatalf("expected 2 get-method calls, got %d", calls)
+	}
+
+	// Без fingerprint пробуются все расширения
+	client.calls.Store(0)
+	if _, err := d.VerifyAndInspect(context.Background(), testMinter, "deadbeef"); err != nil {
+		t.Fatalf("verify returned error: %v", err)
+	}
+	if calls := client.calls.Load(); calls != 5 {
+		t.Fatalf("expected 5 get-method calls without fingerprint, got %d", calls)
+	}
+}
+
+type memoryDeployerHistory struct {
+	entries map[string][][]byte
+}
+
+func (h *memoryDeployerHistory) AppendDeployment(_ context.Context, deployer string, payload []byte) error {
+	h.entries[deployer] = append([][]byte{payload}, h.entries[deployer]...)
+	return nil
+}
+
+func (h *memoryDeployerHistory) Deployments(_ context.Context, deployer string) ([][]byte, error) {
+	return h.entries[deployer], nil
+}
+
+func TestDeployerReputationFlagsSerialScammer(t *testing.T) {
+	deployer := "0:" + strings.Repeat("ab", 32)
+	history := &memoryDeployerHistory{entries: map[string][][]byte{}}
+	rep := NewDeployerReputation(history, nil, nil, zap.NewNop())
+
+	// Первый токен — подделка USDT, он помечается
+	first := &Metadata{Address: "0:first", Symbol: "USDT", Impersonation: &ImpersonationMatch{Symbol: "USDT"}}
+	if stats := rep.Record(context.Background(), first, deployer); stats.Total != 1 || stats.Flagged != 0 {
+		t.Fatalf("unexpected first stats: %+v", stats)
+	}
+
+	second := &Metadata{Address: "0:second", Symbol: "NEW", RiskScore: 10}
+	stats := rep.Record(context.Background(), second, deployer)
+	if stats.Total != 2 || stats.Last24h != 2 || stats.Flagged != 1 || stats.FlaggedMinters[0] != "0:first" {
+		t.Fatalf("unexpected second stats: %+v", stats)
+	}
+	if second.RiskScore != 10+riskWeights[RiskDeployerFlagged] || second.Deployer != stats {
+		t.Fatalf("deployer risk not applied: score=%d reasons=%v", second.RiskScore, second.RiskReasons)
+	}
+}
+
+func TestDeployerReputationBlocklist(t *testing.T) {
+	deployer := "0:" + strings.Repeat("cd", 32)
+	rep := NewDeployerReputation(&memoryDeployerHistory{entries: map[string][][]byte{}}, nil, []string{deployer}, zap.NewNop())
+
+	meta := &Metadata{Address: "0:minter"}
+	if stats := rep.Record(context.Background(), meta, deployer); stats.List != DeployerBlocked {
+		t.Fatalf("expected blocked deployer, got %+v", stats)
+	}
+	if len(meta.RiskReasons) != 1 || meta.RiskReasons[0] != RiskDeployerBlocked {
+		t.Fatalf("unexpected reasons: %v", meta.RiskReasons)
+	}
+}
+
+func TestCampaignDetectorCollapsesFactorySpam(t *testing.T) {
+	standard := strings.Repeat("ab", 32)
+	c := NewCampaignDetector(10*time.Minute, 3, []string{standard}, zap.NewNop())
+	factory := strings.Repeat("ef", 32)
+	start := time.Now()
+
+	// Разные деплоеры и символы, общий нестандартный код
+	var started, suppressed int
+	for i := 0; i < 5; i++ {
+		meta := &Metadata{Address: fmt.Sprintf("0:m%d", i), CodeHash: factory, Symbol: fmt.Sprintf("T%d", i)}
+		camp, isNew := c.Observe(meta, fmt.Sprintf("0:d%d", i), start.Add(time.Duration(i)*time.Second))
+		if i < 2 && camp != nil {
+			t.Fatalf("deploy %d flagged before threshold", i)
+		}
+		if camp == nil {
+			continue
+		}
+		if camp.By != CampaignByCodeHash || camp.Value != factory {
+			t.Fatalf("unexpected campaign: %+v", camp)
+		}
+		if isNew {
+			started++
+		} else {
+			suppressed++
+		}
+	}
+	if started != 1 || suppressed != 2 {
+		t.Fatalf("expected 1 start and 2 suppressed, got %d/%d", started, suppressed)
+	}
+
+	if finished := c.Expire(start.Add(5 * time.Minute)); len(finished) != 0 {
+		t.Fatalf("campaign finished too early: %+v", finished)
+	}
+	finished := c.Expire(start.Add(20 * time.Minute))
+	if len(finished) != 1 || !finished[0].Finished || finished[0].Count != 5 || finished[0].Suppressed != 2 || len(finished[0].Sample) != 5 {
+		t.Fatalf("unexpected summary: %+v", finished)
 	}
 
-	if meta.Name != "TestName" || meta.Symbol != "TST" || meta.Decimals != 9 {
-		t.Fatalf("unexpected metadata: %+v", meta)
+	// Стандартный код из списка сам по себе кампанией не считается
+	for i := 0; i < 5; i++ {
+		meta := &Metadata{Address: fmt.Sprintf("0:s%d", i), CodeHash: standard, Symbol: fmt.Sprintf("S%d", i)}
+		if camp, _ := c.Observe(meta, fmt.Sprintf("0:sd%d", i), start.Add(time.Hour)); camp != nil {
+			t.Fatalf("standard code flagged as campaign: %+v", camp)
+		}
 	}
 }
 
+func TestCampaignDetectorClockFollowsBlockTime(t *testing.T) {
+	c := NewCampaignDetector(10*time.Minute, 2, nil, zap.NewNop())
+	wall := time.Now()
+	if got := c.Clock(wall); !got.Equal(wall) {
+		t.Fatalf("clock before first deploy must be wall time, got %s", got)
+	}
+
+	// Catchup: блоки трёхчасовой давности, кампания по деплоеру
+	block := wall.Add(-3 * time.Hour)
+	for i := 0; i < 2; i++ {
+		meta := &Metadata{Address: fmt.Sprintf("0:m%d", i), Symbol: fmt.Sprintf("T%d", i)}
+		c.Observe(meta, "0:factory", block.Add(time.Duration(i)*time.Second))
+	}
 
+	// Кампания идёт по времени блоков: через минуту по часам процесса ещё не завершена
+	if finished := c.Expire(c.Clock(time.Now().Add(time.Minute))); len(finished) != 0 {
+		t.Fatalf("campaign expired by wall clock: %+v", finished)
+	}
+	if finished := c.Expire(c.Clock(time.Now().Add(11 * time.Minute))); len(finished) != 1 {
+		t.Fatalf("campaign not expired after window of block time: %+v", finished)
+	}
+}
 
+func TestCampaignDetectorIgnoresCommonCodeWithoutStandardList(t *testing.T) {
+	c := NewCampaignDetector(10*time.Minute, 3, nil, zap.NewNop())
+	reference := strings.Repeat("cd", 32)
+	start := time.Now()
 
+	// Поток легитимных jetton на одном эталонном коде: разные деплоеры, имена и символы
+	for i := 0; i < 20; i++ {
+		meta := &Metadata{
+			Address:  fmt.Sprintf("0:m%d", i),
+			CodeHash: reference,
+			Name:     fmt.Sprintf("Token %d", i),
+			Symbol:   fmt.Sprintf("T%d", i),
+		}
+		if camp, _ := c.Observe(meta, fmt.Sprintf("0:d%d", i), start.Add(time.Duration(i)*time.Second)); camp != nil {
+			t.Fatalf("legitimate minter %d suppressed: %+v", i, camp)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package detector
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/xssnick/tonutils-go/tvm/cell"
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultFingerprintTimeout — общий дедлайн на все пробы одного контракта.
+	defaultFingerprintTimeout = 2 * time.Second
+
+	// Сколько code_hash держит реестр; давно не встречавшийся код вытесняется
+	fingerprintRegistrySize = 50000
+)
+
+// DefaultFingerprintMethods — get-методы, которыми по умолчанию пробуется неизвестный код.
+var DefaultFingerprintMethods = []string{
+	// Jetton (TEP-74, TEP-177, stablecoin)
+	"get_jetton_data",
+	"get_wallet_address",
+	"get_wallet_data",
+	"get_mintless_airdrop_hashmap_root",
+	"get_next_admin_address",
+	"get_status",
+	// NFT (TEP-62, TEP-66)
+	"get_collection_data",
+	"get_nft_address_by_index",
+	"get_nft_data",
+	"royalty_params",
+	// DEX
+	"get_pool_data",
+	"get_assets",
+	"get_reserves",
+	// Кошельки
+	"seqno",
+	"get_public_key",
+	"get_subwallet_id",
+	"get_plugin_list",
+}
+
+// probeArgs возвращает аргументы для get-методов, которые без них не выполняются.
+func probeArgs(method string) []any {
+	switch method {
+	case "get_wallet_address":
+		return []any{cell.BeginCell().MustStoreAddr(probeOwnerAddress()).EndCell().BeginParse()}
+	case "get_nft_address_by_index":
+		return []any{0}
+	}
+	return nil
+}
+
+// InterfaceSet — отсортированный список get-методов, на которые контракт ответил.
+type InterfaceSet []string
+
+// Has проверяет наличие метода в наборе.
+func (s InterfaceSet) Has(method string) bool {
+	i := sort.SearchStrings(s, method)
+	return i < len(s) && s[i] == method
+}
+
+// HasAny проверяет наличие хотя бы одного из методов.
+func (s InterfaceSet) HasAny(methods ...string) bool {
+	for _, m := range methods {
+		if s.Has(m) {
+			return true
+		}
+	}
+	return false
+}
+
+// Fingerprinter определяет набор интерфейсов контракта, параллельно пробуя get-методы.
+// Результат кэшируется по code_hash: один и тот же код не пробуется дважды, пока его
+// не вытеснил из реестра более свежий код.
+type Fingerprinter struct {
+	fetcher MetadataFetcher
+	methods []string
+	timeout time.Duration
+	logger  *zap.Logger
+
+	mu       sync.Mutex                           // проверка и вставка в реестр одной операцией
+	registry *lruCache[string, *fingerprintEntry] // code_hash -> набор интерфейсов
+}
+
+// fingerprintEntry — запись реестра; done закрывается, когда пробы завершены.
+type fingerprintEntry struct {
+	done chan struct{}
+	set  InterfaceSet
+}
+
+// NewFingerprinter создаёт модуль fingerprinting.
+// Пустой methods — DefaultFingerprintMethods, timeout <= 0 — 2 секунды.
+func NewFingerprinter(fetcher MetadataFetcher, methods []string, timeout time.Duration, logger *zap.Logger) *Fingerprinter {
+	if len(methods) == 0 {
+		methods = DefaultFingerprintMethods
+	}
+	if timeout <= 0 {
+		timeout = defaultFingerprintTimeout
+	}
+
+	return &Fingerprinter{
+		fetcher:  fetcher,
+		methods:  methods,
+		timeout:  timeout,
+		logger:   logger,
+		registry: newLRUCache[string, *fingerprintEntry](fingerprintRegistrySize),
+	}
+}
+
+// Fingerprint возвращает набор интерфейсов контракта.
+// Для уже виденного code_hash результат берётся из реестра без обращения к сети;
+// параллельные вызовы с тем же code_hash ждут первую пробу.
+func (f *Fingerprinter) Fingerprint(ctx context.Context, target Target) InterfaceSet {
+	codeHash := strings.ToLower(target.CodeHash)
+
+	f.mu.Lock()
+	if entry, ok := f.registry.Get(codeHash); ok && codeHash != "" {
+		f.mu.Unlock()
+		select {
+		case <-entry.done:
+			return entry.set
+		case <-ctx.Done():
+			return nil
+		}
+	}
+
+	entry := &fingerprintEntry{done: make(chan struct{})}
+	if codeHash != "" {
+		f.registry.Add(codeHash, entry)
+	}
+	f.mu.Unlock()
+
+	set, complete := f.probe(ctx, target.Address)
+	if complete {
+		entry.set = set
+	}
+	close(entry.done)
+
+	// Пробы прерваны вызывающим (shutdown) или временным сбоем — неполный результат
+	// не кэшируем и не отдаём: nil оставляет проверку самим детекторам
+	if (!complete || ctx.Err() != nil) && codeHash != "" {
+		f.mu.Lock()
+		if current, ok := f.registry.Get(codeHash); ok && current == entry {
+			f.registry.Remove(codeHash)
+		}
+		f.mu.Unlock()
+	}
+
+	f.logger.Debug("fingerprint контракта",
+		zap.String("address", target.Address),
+		zap.String("code_hash", codeHash),
+		zap.Strings("interfaces", entry.set),
+	)
+
+	return entry.set
+}
+
+// Lookup возвращает закэшированный набор интерфейсов для code_hash.
+func (f *Fingerprinter) Lookup(codeHash string) (InterfaceSet, bool) {
+	entry, ok := f.registry.Get(strings.ToLower(codeHash))
+	if !ok {
+		return nil, false
+	}
+
+	select {
+	case <-entry.done:
+		return entry.set, true
+	default:
+		return nil, false
+	}
+}
+
+// probe параллельно вызывает все методы с общим дедлайном.
+// complete = false, если хотя бы одна проба упала не кодом выхода контракта (см. isTransient):
+// отсутствие метода кэшируется навсегда, поэтому фиксируется только по ответу контракта.
+func (f *Fingerprinter) probe(ctx context.Context, addr string) (InterfaceSet, bool) {
+	probeCtx, cancel := context.WithTimeout(ctx, f.timeout)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		set      = make(InterfaceSet, 0, len(f.methods))
+		complete = true
+	)
+
+	for _, method := range f.methods {
+		wg.Add(1)
+		go func(method string) {
+			defer wg.Done()
+			_, err := f.fetcher.RunGetMethod(probeCtx, addr, method, probeArgs(method)...)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if isTransient(err) {
+					complete = false
+				}
+				return
+			}
+			set = append(set, method)
+		}(method)
+	}
+
+	wg.Wait()
+	sort.Strings(set)
+	return set, complete
+}
@@ -0,0 +1,247 @@
+package detector
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/xssnick/tonutils-go/address"
+	"github.com/yourname/hyper-sniper-indexer/pkg/ton"
+	"go.uber.org/zap"
+)
+
+// Способы совпадения с защищённым токеном.
+const (
+	MatchExact        = "exact"         // тикер/имя совпали как есть (без учёта регистра)
+	MatchHomoglyph    = "homoglyph"     // совпали после замены похожих символов
+	MatchEditDistance = "edit_distance" // отличаются на 1-2 символа
+	MatchContains     = "contains"      // имя содержит имя защищённого токена
+)
+
+const (
+	// Нечёткое сравнение тикеров только от этой длины: у коротких слишком много соседей (NOT/HOT/NET)
+	minFuzzySymbolLen = 4
+
+	// Для имён допускаем больше правок и поиск подстроки
+	minFuzzyNameLen = 6
+)
+
+// ProtectedToken — токен, подделки которого надо ловить, и адрес его настоящего минтера.
+type ProtectedToken struct {
+	Symbol string
+	Name   string
+	Minter string
+}
+
+// ImpersonationMatch описывает совпадение минтера с защищённым токеном.
+type ImpersonationMatch struct {
+	Symbol          string // тикер защищённого токена
+	CanonicalMinter string // адрес настоящего минтера
+	Field           string // symbol / name
+	Method          string // Match*
+	Distance        int    // расстояние редактирования после нормализации
+}
+
+// DefaultProtectedTokens возвращает встроенный список защищённых токенов mainnet.
+func DefaultProtectedTokens() []ProtectedToken {
+	return []ProtectedToken{
+		{Symbol: "USDT", Name: "Tether USD", Minter: "EQCxE6mUtQJKFnGfaROTKOt1lZbDiiX1kCixRv7Nw2Id_sDs"},
+		{Symbol: "NOT", Name: "Notcoin", Minter: "EQAvlWFDxGF2lXm67y4yzC17wYKD9A0guwPkMs1gOsM__NOT"},
+		{Symbol: "STON", Name: "STON", Minter: "EQA2kCVNwVsil2EM2mB0SkXytxCqQjS4mttjDpnXmwG9T6bO"},
+	}
+}
+
+// protectedEntry — защищённый токен с заранее нормализованными полями.
+type protectedEntry struct {
+	token  ProtectedToken
+	minter *address.Address
+	symbol string
+	name   string
+}
+
+// ImpersonationChecker сравнивает name/symbol нового минтера со списком защищённых токенов.
+type ImpersonationChecker struct {
+	tokens []protectedEntry
+	logger *zap.Logger
+}
+
+// NewImpersonationChecker создаёт проверку подделок.
+func NewImpersonationChecker(tokens []ProtectedToken, logger *zap.Logger) *ImpersonationChecker {
+	entries := make([]protectedEntry, 0, len(tokens))
+	for _, token := range tokens {
+		entry := protectedEntry{
+			token:  token,
+			symbol: normalizeTicker(token.Symbol),
+			name:   normalizeTicker(token.Name),
+		}
+		if addr, err := ton.ParseAddress(token.Minter); err == nil {
+			entry.minter = addr
+		} else {
+			logger.Warn("некорректный адрес минтера защищённого токена",
+				zap.String("symbol", token.Symbol),
+				zap.String("minter", token.Minter),
+			)
+		}
+		entries = append(entries, entry)
+	}
+
+	return &ImpersonationChecker{
+		tokens: entries,
+		logger: logger,
+	}
+}
+
+// Check ищет защищённый токен, под который маскируется минтер, и записывает его в meta.Impersonation.
+// Настоящий минтер токена подделкой не считается.
+func (c *ImpersonationChecker) Check(meta *Metadata) *ImpersonationMatch {
+	if meta.Name == "" && meta.Symbol == "" {
+		return nil
+	}
+
+	addr, _ := ton.ParseAddress(meta.Address)
+	symbol, name := normalizeTicker(meta.Symbol), normalizeTicker(meta.Name)
+
+	for _, entry := range c.tokens {
+		if addr != nil && entry.minter != nil && addr.Equals(entry.minter) {
+			return nil
+		}
+
+		match := matchSymbol(meta.Symbol, symbol, entry)
+		if match == nil {
+			match = matchName(meta.Name, name, entry)
+		}
+		if match == nil {
+			continue
+		}
+
+		meta.Impersonation = match
+		c.logger.Info("минтер маскируется под защищённый токен",
+			zap.String("address", meta.Address),
+			zap.String("name", meta.Name),
+			zap.String("symbol", meta.Symbol),
+			zap.String("protected", match.Symbol),
+			zap.String("method", match.Method),
+		)
+		return match
+	}
+
+	return nil
+}
+
+func matchSymbol(raw, normalized string, entry protectedEntry) *ImpersonationMatch {
+	if normalized == "" || entry.symbol == "" {
+		return nil
+	}
+
+	method := ""
+	distance := editDistance(normalized, entry.symbol)
+	switch {
+	case strings.EqualFold(strings.TrimSpace(raw), entry.token.Symbol):
+		method = MatchExact
+	case distance == 0:
+		method = MatchHomoglyph
+	case len(entry.symbol) >= minFuzzySymbolLen && distance <= 1:
+		method = MatchEditDistance
+	default:
+		return nil
+	}
+
+	return newMatch(entry, "symbol", method, distance)
+}
+
+func matchName(raw, normalized string, entry protectedEntry) *ImpersonationMatch {
+	if normalized == "" || entry.name == "" {
+		return nil
+	}
+
+	method := ""
+	distance := editDistance(normalized, entry.name)
+	switch {
+	case strings.EqualFold(strings.TrimSpace(raw), entry.token.Name):
+		method = MatchExact
+	case distance == 0:
+		method = MatchHomoglyph
+	case len(entry.name) >= minFuzzyNameLen && distance <= 2:
+		method = MatchEditDistance
+	case len(entry.name) >= minFuzzyNameLen && strings.Contains(normalized, entry.name):
+		method = MatchContains
+	default:
+		return nil
+	}
+
+	return newMatch(entry, "name", method, distance)
+}
+
+func newMatch(entry protectedEntry, field, method string, distance int) *ImpersonationMatch {
+	return &ImpersonationMatch{
+		Symbol:          entry.token.Symbol,
+		CanonicalMinter: entry.token.Minter,
+		Field:           field,
+		Method:          method,
+		Distance:        distance,
+	}
+}
+
+// homoglyphs сводит похожие символы (кириллица, греческий, цифры-заменители) к латинице.
+var homoglyphs = map[rune]rune{
+	// Кириллица
+	'а': 'a', 'в': 'b', 'е': 'e', 'ё': 'e', 'к': 'k', 'м': 'm', 'н': 'h', 'о': 'o',
+	'р': 'p', 'с': 'c', 'т': 't', 'у': 'y', 'х': 'x', 'ѕ': 's', 'і': 'i', 'ј': 'j',
+	// Греческий
+	'α': 'a', 'β': 'b', 'ε': 'e', 'ι': 'i', 'κ': 'k', 'ν': 'v', 'ο': 'o', 'ρ': 'p',
+	'τ': 't', 'υ': 'u', 'χ': 'x',
+	// Цифры и знаки
+	'0': 'o', '1': 'l', '3': 'e', '5': 's', '$': 's', '@': 'a', '|': 'l', '₮': 't',
+	// Похожие латинские
+	'ı': 'i', 'ℓ': 'l',
+}
+
+// normalizeTicker приводит строку к каноническому виду: регистр, полноширинные формы,
+// гомоглифы; всё, кроме букв и цифр (пробелы, точки, zero-width, эмодзи), отбрасывается.
+func normalizeTicker(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		// Полноширинные ASCII (Ｕ Ｓ Ｄ Ｔ)
+		if r >= 0xFF01 && r <= 0xFF5E {
+			r = unicode.ToLower(r - 0xFEE0)
+		}
+		if mapped, ok := homoglyphs[r]; ok {
+			r = mapped
+		}
+		// i и l в тикерах взаимозаменяемы визуально
+		if r == 'i' {
+			r = 'l'
+		}
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// editDistance — расстояние Дамерау-Левенштейна (с перестановкой соседних символов: USDT/USTD = 1).
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev2 := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				cur[j] = min(cur[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+
+	return prev[len(rb)]
+}
@@ -0,0 +1,73 @@
+package detector
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// NFTCollection описывает коллекцию TEP-62.
+type NFTCollection struct {
+	NextItemIndex string // количество выпущенных NFT
+	ContentURI    string // URI метаданных коллекции (offchain)
+	OwnerAddr     string
+}
+
+// NFTDetector распознаёт NFT-коллекции по get_collection_data (TEP-62).
+type NFTDetector struct {
+	fetcher MetadataFetcher
+	logger  *zap.Logger
+}
+
+// NewNFTDetector создаёт детектор NFT-коллекций.
+func NewNFTDetector(fetcher MetadataFetcher, logger *zap.Logger) *NFTDetector {
+	return &NFTDetector{
+		fetcher: fetcher,
+		logger:  logger,
+	}
+}
+
+// Name реализует ContractDetector.
+func (n *NFTDetector) Name() string {
+	return string(KindNFTCollection)
+}
+
+// Detect реализует ContractDetector.
+// По TEP-62 get_collection_data возвращает:
+// (int next_item_index, cell collection_content, slice owner_address)
+func (n *NFTDetector) Detect(ctx context.Context, target Target) (*Classification, error) {
+	if target.Interfaces != nil && !target.Interfaces.Has("get_collection_data") {
+		return nil, ErrNotMatched
+	}
+
+	checkCtx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	result, err := n.fetcher.RunGetMethod(checkCtx, target.Address, "get_collection_data")
+	if err != nil || len(result) < 3 {
+		return nil, ErrNotMatched
+	}
+
+	collection := &NFTCollection{
+		NextItemIndex: bytesToBigIntString(result[0]),
+		ContentURI:    extractContentURI(result[1]),
+		OwnerAddr:     parseAddressFromBytes(result[2]),
+	}
+
+	n.logger.Debug("get_collection_data успешно",
+		zap.String("address", target.Address),
+		zap.String("next_item_index", collection.NextItemIndex),
+	)
+
+	return &Classification{
+		Kind:       KindNFTCollection,
+		Detector:   n.Name(),
+		Address:    target.Address,
+		CodeHash:   strings.ToLower(target.CodeHash),
+		Timestamp:  time.Now().UTC(),
+		Interfaces: target.Interfaces,
+		NFT:        collection,
+	}, nil
+}
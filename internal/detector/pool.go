@@ -0,0 +1,266 @@
+package detector
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/xssnick/tonutils-go/address"
+	"github.com/xssnick/tonutils-go/tvm/cell"
+	"github.com/yourname/hyper-sniper-indexer/pkg/ton"
+	"go.uber.org/zap"
+)
+
+// Идентификаторы DEX.
+const (
+	DexStonfi = "stonfi"
+	DexDeDust = "dedust"
+)
+
+// NativeTON обозначает TON в паре DeDust (asset native$0000).
+const NativeTON = "native"
+
+// Адрес минтера pTON v1: TON в парах STON.fi.
+const stonfiPTON = "0:8cdc1d7640ad5ee326527fc1ad0514f468b30dc84b0173f0e155f451b4e11f7c"
+
+// События пулов для отслеживаемых jetton.
+const (
+	PoolCreated    = "pool_created"
+	LiquidityAdded = "liquidity_added"
+	LPBurned       = "lp_burned"
+	LPLocked       = "lp_locked"
+)
+
+// BurnAddress — нулевой адрес: LP-токены, отправленные на него, выведены из оборота.
+// Сжигание LP опкодом burn на STON.fi и DeDust — это вывод ликвидности, а не блокировка.
+const BurnAddress = "0:0000000000000000000000000000000000000000000000000000000000000000"
+
+// DefaultDexRouters возвращает известные контракты, деплоящие пулы: роутер STON.fi v1 и фабрику DeDust.
+func DefaultDexRouters() map[string]string {
+	return map[string]string{
+		"0:779dcc815138d9500e449c5291e7f12738c23d575b5310000f6a253bd607384e": DexStonfi,
+		"0:5f0564fb5f604783db57031ce1cf668a88d4d4d6da6de4db222b4b920d6fd800": DexDeDust,
+	}
+}
+
+// PoolEvent — создание пула или добавление ликвидности для недавно найденного jetton.
+type PoolEvent struct {
+	Kind   string // pool_created / liquidity_added
+	Pool   string
+	Minter string
+	PoolInfo
+
+	JettonIndex int    // 0 или 1: какой токен пула — наш jetton
+	Quote       string // минтер второго токена или NativeTON
+	Price       string // цена 1 jetton в TON (пусто, если пара не с TON или резервы пустые)
+
+	Provider string // liquidity_added: кто внёс ликвидность
+	Amount0  string
+	Amount1  string
+	First    bool // первое добавление ликвидности в пул
+
+	// lp_burned / lp_locked
+	LPOwner   string // владелец, отправивший LP
+	LPWallet  string
+	LPAmount  string
+	LPSupply  string  // total_supply LP на момент перевода (пусто — не прочитан)
+	LPPercent float64 // доля LP от supply, %
+	Locker    string  // название локера из конфига
+}
+
+// PoolInfo описывает пул ликвидности DEX.
+type PoolInfo struct {
+	DEX      string // stonfi / dedust
+	Token0   string // STON.fi: jetton-кошелёк роутера; DeDust: jetton master или "native"
+	Token1   string
+	Reserve0 string
+	Reserve1 string
+}
+
+// PoolDetector распознаёт пулы STON.fi (get_pool_data) и DeDust (get_assets + get_reserves).
+type PoolDetector struct {
+	fetcher MetadataFetcher
+	logger  *zap.Logger
+}
+
+// NewPoolDetector создаёт детектор пулов.
+func NewPoolDetector(fetcher MetadataFetcher, logger *zap.Logger) *PoolDetector {
+	return &PoolDetector{
+		fetcher: fetcher,
+		logger:  logger,
+	}
+}
+
+// Name реализует ContractDetector.
+func (p *PoolDetector) Name() string {
+	return string(KindDexPool)
+}
+
+// Detect реализует ContractDetector.
+func (p *PoolDetector) Detect(ctx context.Context, target Target) (*Classification, error) {
+	if target.Interfaces != nil && !target.Interfaces.HasAny("get_pool_data", "get_assets") {
+		return nil, ErrNotMatched
+	}
+
+	checkCtx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	pool := p.inspectStonfi(checkCtx, target.Address)
+	if pool == nil {
+		pool = p.inspectDeDust(checkCtx, target.Address)
+	}
+	if pool == nil {
+		return nil, ErrNotMatched
+	}
+
+	p.logger.Debug("обнаружен пул DEX",
+		zap.String("address", target.Address),
+		zap.String("dex", pool.DEX),
+		zap.String("token0", pool.Token0),
+		zap.String("token1", pool.Token1),
+	)
+
+	return &Classification{
+		Kind:       KindDexPool,
+		Detector:   p.Name(),
+		Address:    target.Address,
+		CodeHash:   strings.ToLower(target.CodeHash),
+		Timestamp:  time.Now().UTC(),
+		Interfaces: target.Interfaces,
+		Pool:       pool,
+	}, nil
+}
+
+// Inspect читает состав и резервы пула указанного DEX.
+func (p *PoolDetector) Inspect(ctx context.Context, dex, addr string) *PoolInfo {
+	if dex == DexDeDust {
+		return p.inspectDeDust(ctx, addr)
+	}
+	return p.inspectStonfi(ctx, addr)
+}
+
+// JettonMaster возвращает минтер jetton-кошелька: get_wallet_data -> (balance, owner, jetton, wallet_code).
+// pTON отдаётся как NativeTON.
+func (p *PoolDetector) JettonMaster(ctx context.Context, wallet string) string {
+	result, err := p.fetcher.RunGetMethod(ctx, wallet, "get_wallet_data")
+	if err != nil || len(result) < 3 {
+		return ""
+	}
+	master := parseAddress(result[2])
+	if master == nil {
+		return ""
+	}
+	if raw := ton.RawAddress(master); raw != stonfiPTON {
+		return raw
+	}
+	return NativeTON
+}
+
+// PoolPrice считает цену 1 jetton в TON по резервам: reserveTON/1e9 / (reserveJetton/10^decimals).
+func PoolPrice(reserveJetton, reserveTON string, decimals int) string {
+	j, ok1 := new(big.Rat).SetString(reserveJetton)
+	t, ok2 := new(big.Rat).SetString(reserveTON)
+	if !ok1 || !ok2 || j.Sign() <= 0 || t.Sign() <= 0 {
+		return ""
+	}
+	scale := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil))
+	nano := new(big.Rat).SetInt64(1_000_000_000)
+
+	price := new(big.Rat).Quo(t, nano)
+	price.Quo(price, new(big.Rat).Quo(j, scale))
+	return price.FloatString(12)
+}
+
+// SharePercent возвращает part/total в процентах (0 при пустом или нулевом total).
+func SharePercent(part, total string) float64 {
+	p, ok1 := new(big.Rat).SetString(part)
+	t, ok2 := new(big.Rat).SetString(total)
+	if !ok1 || !ok2 || t.Sign() <= 0 {
+		return 0
+	}
+	share, _ := new(big.Rat).Mul(new(big.Rat).Quo(p, t), big.NewRat(100, 1)).Float64()
+	return share
+}
+
+// inspectStonfi проверяет пул STON.fi v1.
+// get_pool_data: (reserve0, reserve1, token0_address, token1_address, lp_fee, protocol_fee, ...)
+func (p *PoolDetector) inspectStonfi(ctx context.Context, addr string) *PoolInfo {
+	result, err := p.fetcher.RunGetMethod(ctx, addr, "get_pool_data")
+	if err != nil || len(result) < 4 {
+		return nil
+	}
+
+	token0, token1 := parseAddress(result[2]), parseAddress(result[3])
+	if token0 == nil || token1 == nil {
+		return nil
+	}
+
+	return &PoolInfo{
+		DEX:      DexStonfi,
+		Token0:   ton.RawAddress(token0),
+		Token1:   ton.RawAddress(token1),
+		Reserve0: bytesToBigIntString(result[0]),
+		Reserve1: bytesToBigIntString(result[1]),
+	}
+}
+
+// inspectDeDust проверяет пул DeDust: get_assets -> (asset0, asset1), get_reserves -> (r0, r1).
+func (p *PoolDetector) inspectDeDust(ctx context.Context, addr string) *PoolInfo {
+	assets, err := p.fetcher.RunGetMethod(ctx, addr, "get_assets")
+	if err != nil || len(assets) < 2 {
+		return nil
+	}
+
+	asset0, ok0 := parseDeDustAsset(assets[0])
+	asset1, ok1 := parseDeDustAsset(assets[1])
+	if !ok0 || !ok1 {
+		return nil
+	}
+
+	pool := &PoolInfo{
+		DEX:      DexDeDust,
+		Token0:   asset0,
+		Token1:   asset1,
+		Reserve0: "0",
+		Reserve1: "0",
+	}
+
+	if reserves, err := p.fetcher.RunGetMethod(ctx, addr, "get_reserves"); err == nil && len(reserves) >= 2 {
+		pool.Reserve0 = bytesToBigIntString(reserves[0])
+		pool.Reserve1 = bytesToBigIntString(reserves[1])
+	}
+
+	return pool
+}
+
+// parseDeDustAsset разбирает Asset DeDust: native$0000 | jetton$0001 workchain:int8 address:uint256.
+func parseDeDustAsset(b []byte) (string, bool) {
+	c, err := cell.FromBOC(b)
+	if err != nil {
+		return "", false
+	}
+
+	s := c.BeginParse()
+	tag, err := s.LoadUInt(4)
+	if err != nil {
+		return "", false
+	}
+
+	switch tag {
+	case 0:
+		return NativeTON, true
+	case 1:
+		wc, err := s.LoadInt(8)
+		if err != nil {
+			return "", false
+		}
+		hash, err := s.LoadSlice(256)
+		if err != nil {
+			return "", false
+		}
+		return ton.RawAddress(address.NewAddress(0, byte(wc), hash)), true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,399 @@
+package detector
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/xssnick/tonutils-go/tvm/cell"
+	"github.com/yourname/hyper-sniper-indexer/pkg/ton"
+	"go.uber.org/zap"
+)
+
+// Коды причин риска (стабильны, их читает SafetyChecker бота).
+const (
+	RiskMintableWithAdmin   = "mintable_with_admin"
+	RiskAdminIsWallet       = "admin_is_wallet"
+	RiskAdminIsContract     = "admin_is_contract"
+	RiskUnknownWalletCode   = "unknown_wallet_code"
+	RiskSuspiciousWallet    = "suspicious_wallet_code"
+	RiskNonStandardMinter   = "non_standard_minter"
+	RiskDeployerHoldsSupply = "deployer_holds_supply"
+	RiskMetadataMissing     = "metadata_missing"
+	RiskMetadataUnreachable = "metadata_unreachable"
+	RiskDeployerFlagged     = "deployer_flagged_before"
+	RiskDeployerBurst       = "deployer_burst"
+	RiskDeployerBlocked     = "deployer_blocklisted"
+)
+
+// riskWeights задаёт вклад каждой причины в итоговый score (0-100).
+var riskWeights = map[string]int{
+	RiskMintableWithAdmin:   25,
+	RiskAdminIsWallet:       10,
+	RiskAdminIsContract:     5,
+	RiskUnknownWalletCode:   20,
+	RiskSuspiciousWallet:    30,
+	RiskNonStandardMinter:   10,
+	RiskDeployerHoldsSupply: 20,
+	RiskMetadataMissing:     15,
+	RiskMetadataUnreachable: 10,
+	RiskDeployerFlagged:     30,
+	RiskDeployerBurst:       15,
+	RiskDeployerBlocked:     50,
+}
+
+const (
+	// Доля supply у деплоера, начиная с которой это считается концентрацией (%)
+	deployerSupplyThreshold = 50
+
+	// Таймаут на проверку доступности URI метаданных
+	metadataCheckTimeout = 3 * time.Second
+
+	// Сколько байт JSON метаданных читаем
+	metadataMaxBytes = 64 << 10
+
+	// Публичный шлюз для ipfs:// ссылок
+	ipfsGateway = "https://ipfs.io/ipfs/"
+
+	// Редиректов не больше этого числа
+	metadataMaxRedirects = 3
+
+	// Результат загрузки (в том числе неудачной) кэшируется по URI: спам-кампании
+	// ставят один и тот же URI, и медленный хост не должен занимать воркер на каждом деплое
+	metadataCacheSize = 10000
+	metadataCacheTTL  = 10 * time.Minute
+)
+
+// errPrivateHost — URI метаданных ведёт на непубличный адрес.
+var errPrivateHost = errors.New("непубличный адрес")
+
+// cgnatRange — 100.64.0.0/10 (carrier-grade NAT), net.IP.IsPrivate его не покрывает.
+var cgnatRange = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// metadataResult — итог загрузки JSON метаданных по URI.
+type metadataResult struct {
+	Name                string `json:"name"`
+	Symbol              string `json:"symbol"`
+	CustomPayloadAPIURI string `json:"custom_payload_api_uri"`
+
+	err     error
+	fetched time.Time
+}
+
+// AccountFetcher расширяет MetadataFetcher получением code_hash аккаунта.
+type AccountFetcher interface {
+	MetadataFetcher
+	GetCodeHash(ctx context.Context, address string) (string, error)
+}
+
+// RiskAnalyzer оценивает риск скама/honeypot для найденного минтера.
+type RiskAnalyzer struct {
+	fetcher    AccountFetcher
+	httpClient *http.Client
+	metadata   *lruCache[string, *metadataResult] // URI -> результат загрузки
+	logger     *zap.Logger
+
+	walletCodeHashes map[string]string // code_hash кошельков (v3/v4/v5) -> версия
+}
+
+// NewRiskAnalyzer создаёт анализатор риска.
+func NewRiskAnalyzer(fetcher AccountFetcher, logger *zap.Logger) *RiskAnalyzer {
+	return &RiskAnalyzer{
+		fetcher:          fetcher,
+		httpClient:       newMetadataHTTPClient(),
+		metadata:         newLRUCache[string, *metadataResult](metadataCacheSize),
+		logger:           logger,
+		walletCodeHashes: defaultWalletCodeHashes(),
+	}
+}
+
+// Assess вычисляет score и причины риска и записывает их в meta.
+// deployer — отправитель сообщения со StateInit (может быть пустым).
+func (r *RiskAnalyzer) Assess(ctx context.Context, meta *Metadata, deployer string) {
+	reasons := make([]string, 0)
+
+	// 1. Минт открыт и есть живой админ
+	if meta.Mintable && meta.AdminAddr != "" {
+		reasons = append(reasons, RiskMintableWithAdmin)
+	}
+
+	// 2. Кто админ: кошелёк или контракт
+	if meta.AdminAddr != "" {
+		if reason := r.classifyAdmin(ctx, meta.AdminAddr); reason != "" {
+			reasons = append(reasons, reason)
+		}
+	}
+
+	// 3. Код jetton-кошелька не из каталога стандартных (и, возможно, блокирует продажу)
+	if wc := meta.WalletCode; wc != nil && !wc.Known {
+		if wc.Checked {
+			reasons = append(reasons, RiskUnknownWalletCode)
+		}
+		if len(wc.Suspicious) > 0 {
+			reasons = append(reasons, RiskSuspiciousWallet)
+		}
+	}
+
+	// 4. Нестандартный код минтера
+	if !meta.KnownCodeHash {
+		reasons = append(reasons, RiskNonStandardMinter)
+	}
+
+	// 5. Supply сосредоточен у деплоера
+	if deployer != "" && r.deployerHoldsSupply(ctx, meta, deployer) {
+		reasons = append(reasons, RiskDeployerHoldsSupply)
+	}
+
+	// 6. Метаданные отсутствуют или недоступны
+	if reason := r.checkMetadata(ctx, meta); reason != "" {
+		reasons = append(reasons, reason)
+	}
+
+	score := 0
+	for _, reason := range reasons {
+		score += riskWeights[reason]
+	}
+	if score > 100 {
+		score = 100
+	}
+
+	meta.RiskScore = score
+	meta.RiskReasons = reasons
+
+	r.logger.Debug("оценка риска",
+		zap.String("address", meta.Address),
+		zap.Int("score", score),
+		zap.Strings("reasons", reasons),
+	)
+}
+
+// classifyAdmin определяет, является ли админ кошельком или контрактом.
+func (r *RiskAnalyzer) classifyAdmin(ctx context.Context, admin string) string {
+	checkCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	codeHash, err := r.fetcher.GetCodeHash(checkCtx, admin)
+	if err != nil {
+		// Неактивный аккаунт — это почти всегда ещё не задеплоенный кошелёк.
+		// Таймаут или сбой liteserver'а ничего не говорит об админе
+		if errors.Is(err, ton.ErrNotReady) && checkCtx.Err() == nil {
+			return RiskAdminIsWallet
+		}
+		r.logger.Debug("не удалось определить тип админа",
+			zap.String("admin", admin),
+			zap.Error(err),
+		)
+		return ""
+	}
+
+	if _, ok := r.walletCodeHashes[strings.ToLower(codeHash)]; ok {
+		return RiskAdminIsWallet
+	}
+
+	// Нестандартный кошелёк всё равно отвечает на get_public_key
+	if _, err := r.fetcher.RunGetMethod(checkCtx, admin, "get_public_key"); err == nil {
+		return RiskAdminIsWallet
+	}
+
+	return RiskAdminIsContract
+}
+
+// deployerHoldsSupply проверяет, что у деплоера большая часть supply.
+// Первичный mint в сообщении деплоя виден без запросов. Иначе читается баланс кошелька деплоера
+// на последнем блоке: на блоке деплоя, к которому привязан ctx, кошелька обычно ещё нет.
+func (r *RiskAnalyzer) deployerHoldsSupply(ctx context.Context, meta *Metadata, deployer string) bool {
+	supply, ok := new(big.Int).SetString(meta.TotalSupply, 10)
+	if !ok || supply.Sign() <= 0 {
+		return false
+	}
+
+	owner, err := ton.ParseAddress(deployer)
+	if err != nil {
+		return false
+	}
+
+	if mint := meta.InitialMint; mint != nil && !mint.Aborted {
+		dest, err := ton.ParseAddress(mint.Destination)
+		amount, ok := new(big.Int).SetString(mint.Amount, 10)
+		if err == nil && ok && dest.Equals(owner) && supplyShare(amount, supply) >= deployerSupplyThreshold {
+			return true
+		}
+	}
+
+	checkCtx, cancel := context.WithTimeout(ton.LatestBlock(ctx), 3*time.Second)
+	defer cancel()
+
+	res, err := r.fetcher.RunGetMethod(checkCtx, meta.Address, "get_wallet_address",
+		cell.BeginCell().MustStoreAddr(owner).EndCell().BeginParse())
+	if err != nil || len(res) == 0 {
+		return false
+	}
+
+	wallet := parseAddress(res[0])
+	if wallet == nil {
+		return false
+	}
+
+	// get_wallet_data: (balance, owner, jetton_master, jetton_wallet_code)
+	data, err := r.fetcher.RunGetMethod(checkCtx, ton.RawAddress(wallet), "get_wallet_data")
+	if err != nil || len(data) == 0 {
+		return false
+	}
+
+	return supplyShare(new(big.Int).SetBytes(data[0]), supply) >= deployerSupplyThreshold
+}
+
+// supplyShare возвращает долю amount в supply в процентах.
+func supplyShare(amount, supply *big.Int) int64 {
+	return new(big.Int).Div(new(big.Int).Mul(amount, big.NewInt(100)), supply).Int64()
+}
+
+// checkMetadata проверяет наличие и доступность метаданных.
+// Для off-chain метаданных заодно заполняет name/symbol из JSON (нужны для проверки подделок).
+func (r *RiskAnalyzer) checkMetadata(ctx context.Context, meta *Metadata) string {
+	if meta.ContentURI == "" {
+		if meta.Name == "" && meta.Symbol == "" {
+			return RiskMetadataMissing
+		}
+		// On-chain метаданные — проверять нечего
+		return ""
+	}
+
+	uri := meta.ContentURI
+	if strings.HasPrefix(uri, "ipfs://") {
+		uri = ipfsGateway + strings.TrimPrefix(uri, "ipfs://")
+	}
+	if !strings.HasPrefix(uri, "http://") && !strings.HasPrefix(uri, "https://") {
+		return RiskMetadataUnreachable
+	}
+
+	if err := r.fetchMetadata(ctx, uri, meta); err != nil {
+		r.logger.Debug("URI метаданных недоступен",
+			zap.String("address", meta.Address),
+			zap.String("uri", uri),
+			zap.Error(err),
+		)
+		return RiskMetadataUnreachable
+	}
+
+	return ""
+}
+
+// fetchMetadata дополняет пустые name/symbol (и custom_payload_api_uri для mintless) из JSON по URI.
+// Результат, в том числе ошибка, берётся из кэша, пока не истёк metadataCacheTTL.
+func (r *RiskAnalyzer) fetchMetadata(ctx context.Context, uri string, meta *Metadata) error {
+	res, ok := r.metadata.Get(uri)
+	if !ok || time.Since(res.fetched) >= metadataCacheTTL {
+		res = r.loadMetadata(ctx, uri)
+		// Отмена проверки ничего не говорит о доступности URI — такой результат не кэшируем
+		if ctx.Err() == nil {
+			r.metadata.Add(uri, res)
+		}
+	}
+	if res.err != nil {
+		return res.err
+	}
+
+	if meta.Name == "" {
+		meta.Name = res.Name
+	}
+	if meta.Symbol == "" {
+		meta.Symbol = res.Symbol
+	}
+	if meta.Mintless != nil && meta.Mintless.CustomPayloadAPIURI == "" {
+		meta.Mintless.CustomPayloadAPIURI = res.CustomPayloadAPIURI
+	}
+	return nil
+}
+
+// loadMetadata делает GET на URI метаданных (HEAD поддерживают не все шлюзы).
+// Невалидный JSON не считается ошибкой.
+func (r *RiskAnalyzer) loadMetadata(ctx context.Context, uri string) *metadataResult {
+	res := &metadataResult{fetched: time.Now()}
+
+	checkCtx, cancel := context.WithTimeout(ctx, metadataCheckTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(checkCtx, http.MethodGet, uri, nil)
+	if err != nil {
+		res.err = err
+		return res
+	}
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		res.err = err
+		return res
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		res.err = fmt.Errorf("status %d", resp.StatusCode)
+		return res
+	}
+
+	_ = json.NewDecoder(io.LimitReader(resp.Body, metadataMaxBytes)).Decode(res)
+	return res
+}
+
+// newMetadataHTTPClient создаёт HTTP-клиент для URI метаданных. URI задаёт деплоер минтера,
+// поэтому соединения разрешены только с публичными адресами (и после редиректов), а число
+// редиректов ограничено. Адрес проверяется при соединении, уже после DNS-резолва.
+func newMetadataHTTPClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: metadataCheckTimeout,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !isPublicIP(ip) {
+				return fmt.Errorf("%w: %s", errPrivateHost, host)
+			}
+			return nil
+		},
+	}
+
+	return &http.Client{
+		Timeout: metadataCheckTimeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: metadataCheckTimeout,
+			MaxIdleConns:        16,
+			IdleConnTimeout:     30 * time.Second,
+		},
+		CheckRedirect: func(_ *http.Request, via []*http.Request) error {
+			if len(via) >= metadataMaxRedirects {
+				return fmt.Errorf("больше %d редиректов", metadataMaxRedirects)
+			}
+			return nil
+		},
+	}
+}
+
+// isPublicIP отсекает loopback, частные, link-local (169.254.0.0/16 — метаданные облака),
+// CGNAT, multicast и неуказанные адреса.
+func isPublicIP(ip net.IP) bool {
+	return ip.IsGlobalUnicast() &&
+		!ip.IsPrivate() &&
+		!ip.IsLoopback() &&
+		!ip.IsLinkLocalUnicast() &&
+		!cgnatRange.Contains(ip)
+}
+
+// defaultWalletCodeHashes возвращает code_hash стандартных кошельков TON.
+func defaultWalletCodeHashes() map[string]string {
+	return map[string]string{
+		"84dafa449f98a6987789ba232358072bc0f76dc4524002a5d0918b9a75d2d599": "Wallet V3R2",
+		"feb5ff6820e2ff0d9483e7e0d62c817d846789fb4ae580c878866d959dabd5c0": "Wallet V4R2",
+		"20834b7b72b112147e1b2fb457b84e74d1a30f04f737d4f62a668e9552d2b72f": "Wallet V5R1",
+	}
+}
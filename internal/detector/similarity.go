@@ -0,0 +1,223 @@
+package detector
+
+import (
+	"hash/fnv"
+	"strings"
+	"sync"
+
+	"github.com/xssnick/tonutils-go/tvm/cell"
+)
+
+const (
+	// Число хэш-функций MinHash: точность оценки Jaccard ~ 1/sqrt(64)
+	minHashSize = 64
+
+	// Длина шингла в байтах кода
+	shingleSize = 4
+
+	// Сходство, начиная с которого код относится к существующему семейству
+	familyThreshold = 0.8
+
+	// Лимиты реестра: семейств (Nearest сравнивает с каждым) и code_hash на семейство.
+	// При переполнении вытесняется семейство, которое дольше всех не встречалось,
+	// и самый старый code_hash семейства — такой код снова отнесётся к нему по отпечатку
+	maxCodeFamilies     = 1000
+	maxFamilyCodeHashes = 256
+)
+
+// CodeFingerprint — структурный отпечаток кода, устойчивый к смене констант.
+// Смена константы меняет code_hash (хэши всех родительских ячеек),
+// но затрагивает лишь несколько шинглов, поэтому MinHash почти не меняется.
+type CodeFingerprint struct {
+	MinHash [minHashSize]uint64
+	Cells   int // число уникальных ячеек
+	Bits    int // суммарный размер данных в битах
+}
+
+// FingerprintCode строит отпечаток по дереву ячеек кода.
+func FingerprintCode(code *cell.Cell) *CodeFingerprint {
+	fp := &CodeFingerprint{}
+	for i := range fp.MinHash {
+		fp.MinHash[i] = ^uint64(0)
+	}
+
+	walkCells(code, func(c *cell.Cell) {
+		fp.Cells++
+		fp.Bits += int(c.BitsSize())
+
+		data := cellBytes(c)
+
+		// Шингл формы ячейки: размер данных и число ссылок
+		shape := []byte{0xFF, byte(c.RefsNum()), byte(c.BitsSize() >> 8), byte(c.BitsSize())}
+		fp.add(shingleHash(shape))
+
+		if len(data) < shingleSize {
+			if len(data) > 0 {
+				fp.add(shingleHash(data))
+			}
+			return
+		}
+		for i := 0; i+shingleSize <= len(data); i++ {
+			fp.add(shingleHash(data[i : i+shingleSize]))
+		}
+	})
+
+	return fp
+}
+
+// add учитывает шингл во всех MinHash-слотах.
+func (fp *CodeFingerprint) add(h uint64) {
+	for i := range fp.MinHash {
+		v := mix64(h + uint64(i)*0x9E3779B97F4A7C15)
+		if v < fp.MinHash[i] {
+			fp.MinHash[i] = v
+		}
+	}
+}
+
+// Similarity оценивает сходство двух отпечатков (0..1) как долю совпавших слотов MinHash.
+func (fp *CodeFingerprint) Similarity(other *CodeFingerprint) float64 {
+	if fp == nil || other == nil {
+		return 0
+	}
+	same := 0
+	for i := range fp.MinHash {
+		if fp.MinHash[i] == other.MinHash[i] {
+			same++
+		}
+	}
+	return float64(same) / minHashSize
+}
+
+func shingleHash(b []byte) uint64 {
+	h := fnv.New64a()
+	h.Write(b)
+	return h.Sum64()
+}
+
+// mix64 — финализатор splitmix64, даёт независимые хэш-функции из одного хэша.
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xBF58476D1CE4E5B9
+	x ^= x >> 27
+	x *= 0x94D049BB133111EB
+	x ^= x >> 31
+	return x
+}
+
+// CodeFamily — кластер почти одинакового кода минтеров.
+type CodeFamily struct {
+	Name        string
+	Fingerprint *CodeFingerprint // отпечаток первого кода семейства
+	CodeHashes  []string         // последние code_hash, отнесённые к семейству
+
+	lastUsed uint64 // номер последнего Observe, отнёсшего код к семейству
+}
+
+// CodeFamilies хранит семейства кода и относит к ним новый код.
+type CodeFamilies struct {
+	mu       sync.RWMutex
+	families []*CodeFamily
+	byHash   map[string]*CodeFamily
+	tick     uint64
+}
+
+// NewCodeFamilies создаёт пустой реестр семейств.
+func NewCodeFamilies() *CodeFamilies {
+	return &CodeFamilies{byHash: make(map[string]*CodeFamily)}
+}
+
+// Nearest возвращает ближайшее семейство и сходство с ним.
+func (f *CodeFamilies) Nearest(fp *CodeFingerprint) (*CodeFamily, float64) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.nearest(fp)
+}
+
+// nearest ищет ближайшее семейство. Вызывается под f.mu.
+func (f *CodeFamilies) nearest(fp *CodeFingerprint) (*CodeFamily, float64) {
+	var best *CodeFamily
+	bestScore := 0.0
+	for _, family := range f.families {
+		if score := fp.Similarity(family.Fingerprint); score > bestScore {
+			best, bestScore = family, score
+		}
+	}
+	return best, bestScore
+}
+
+// Observe относит код к ближайшему семейству (если сходство выше порога)
+// или создаёт новое семейство с именем name. Поиск и вставка идут под одной блокировкой,
+// поэтому параллельные деплои одного кода не создают два семейства.
+// Возвращает ближайшее из ранее известных семейств и сходство с ним.
+func (f *CodeFamilies) Observe(codeHash, name string, fp *CodeFingerprint) (*CodeFamily, float64) {
+	codeHash = strings.ToLower(codeHash)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.tick++
+	if family, seen := f.byHash[codeHash]; seen {
+		family.lastUsed = f.tick
+		return family, 1
+	}
+
+	nearest, score := f.nearest(fp)
+	if nearest != nil && score >= familyThreshold {
+		f.addHash(nearest, codeHash)
+		return nearest, score
+	}
+
+	if len(f.families) >= maxCodeFamilies {
+		f.evict()
+	}
+	created := &CodeFamily{Name: name, Fingerprint: fp}
+	f.addHash(created, codeHash)
+	f.families = append(f.families, created)
+
+	return nearest, score
+}
+
+// addHash относит code_hash к семейству, вытесняя самый старый сверх лимита. Вызывается под f.mu.
+func (f *CodeFamilies) addHash(family *CodeFamily, codeHash string) {
+	family.lastUsed = f.tick
+	family.CodeHashes = append(family.CodeHashes, codeHash)
+	f.byHash[codeHash] = family
+	if len(family.CodeHashes) > maxFamilyCodeHashes {
+		delete(f.byHash, family.CodeHashes[0])
+		family.CodeHashes = family.CodeHashes[1:]
+	}
+}
+
+// evict удаляет семейство, которое дольше всех не встречалось. Вызывается под f.mu.
+func (f *CodeFamilies) evict() {
+	oldest := 0
+	for i, family := range f.families {
+		if family.lastUsed < f.families[oldest].lastUsed {
+			oldest = i
+		}
+	}
+	for _, hash := range f.families[oldest].CodeHashes {
+		delete(f.byHash, hash)
+	}
+	f.families = append(f.families[:oldest], f.families[oldest+1:]...)
+}
+
+// Len возвращает число семейств.
+func (f *CodeFamilies) Len() int {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return len(f.families)
+}
+
+// familyName формирует имя нового семейства.
+func familyName(meta *Metadata) string {
+	if meta.KnownCodeHash {
+		return meta.MinterType
+	}
+	short := meta.CodeHash
+	if len(short) > 8 {
+		short = short[:8]
+	}
+	return "family-" + short
+}
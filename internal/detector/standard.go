@@ -0,0 +1,78 @@
+package detector
+
+import (
+	"context"
+	"encoding/hex"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Варианты стандарта jetton.
+const (
+	StandardTEP74    = "tep74"
+	StandardMintless = "tep177_mintless" // mintless jetton: airdrop по merkle-дереву, claim через custom payload
+	StandardGoverned = "governed"        // stablecoin-контракт (USDT): админ может замораживать кошельки
+)
+
+// MintlessInfo — поля mintless-расширения (TEP-177).
+type MintlessInfo struct {
+	MerkleRoot          string // корень хэшмапы airdrop (hex, 32 байта)
+	CustomPayloadAPIURI string // API, выдающий custom payload для claim
+}
+
+// detectStandard определяет вариант стандарта по дополнительным get-методам минтера.
+// Mintless имеет приоритет: mintless-контракты построены на stablecoin и тоже governed.
+// interfaces — методы, ответившие при fingerprint (nil — fingerprint не снимался): остальные не вызываются.
+// Governed ставится только по get_next_admin_address: get_status есть и у посторонних контрактов.
+// Возвращает true, если ответил лишь get_status — такой минтер проверяется по layout кошелька.
+func (d *Detector) detectStandard(ctx context.Context, addr string, meta *Metadata, data *JettonData, interfaces InterfaceSet) bool {
+	checkCtx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	answered := func(method string) bool {
+		return interfaces == nil || interfaces.Has(method)
+	}
+
+	meta.Standard = StandardTEP74
+
+	// Governed: get_next_admin_address (stablecoin), у части форков — только get_status
+	statusOnly := false
+	if answered("get_next_admin_address") {
+		if result, err := d.fetcher.RunGetMethod(checkCtx, addr, "get_next_admin_address"); err == nil && len(result) > 0 {
+			meta.Governed = true
+			meta.Standard = StandardGoverned
+			meta.NextAdminAddr = parseAddressFromBytes(result[0])
+		}
+	}
+	if !meta.Governed && answered("get_status") {
+		if result, err := d.fetcher.RunGetMethod(checkCtx, addr, "get_status"); err == nil && len(result) > 0 {
+			statusOnly = true
+		}
+	}
+
+	// Mintless: get_mintless_airdrop_hashmap_root -> uint256
+	if answered("get_mintless_airdrop_hashmap_root") {
+		if result, err := d.fetcher.RunGetMethod(checkCtx, addr, "get_mintless_airdrop_hashmap_root"); err == nil && len(result) > 0 {
+			root := make([]byte, 32)
+			if len(result[0]) <= len(root) {
+				copy(root[len(root)-len(result[0]):], result[0])
+			}
+
+			meta.Standard = StandardMintless
+			meta.Mintless = &MintlessInfo{
+				MerkleRoot:          hex.EncodeToString(root),
+				CustomPayloadAPIURI: data.CustomPayloadAPIURI,
+			}
+		}
+	}
+
+	if meta.Standard != StandardTEP74 {
+		d.logger.Debug("расширенный стандарт jetton",
+			zap.String("address", addr),
+			zap.String("standard", meta.Standard),
+			zap.Bool("governed", meta.Governed),
+		)
+	}
+	return statusOnly
+}
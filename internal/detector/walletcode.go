@@ -0,0 +1,196 @@
+package detector
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+
+	"github.com/xssnick/tonutils-go/tvm/cell"
+	"go.uber.org/zap"
+)
+
+// Подозрительные паттерны в коде jetton-кошелька.
+const (
+	WalletPatternDictLookup   = "dict_lookup"   // словарь в кошельке — типичный blacklist/whitelist
+	WalletPatternPauseFlag    = "pause_flag"    // проверка бита статуса перед throw — пауза переводов
+	WalletPatternTimeCheck    = "time_check"    // NOW в кошельке — блокировка по времени
+	WalletPatternSenderChecks = "sender_checks" // лишние сравнения адресов — переводы только владельцу/админу
+)
+
+// Стандартный кошелёк TEP-74 сравнивает адреса не больше этого числа раз
+// (owner, jetton_master, вычисленный адрес кошелька отправителя).
+const standardSenderChecks = 3
+
+// WalletCodeInfo — классификация кода jetton-кошелька из get_jetton_data.
+type WalletCodeInfo struct {
+	Hash       string   // hash jetton_wallet_code
+	Checked    bool     // hash сверен с непустым каталогом; иначе Known ничего не значит
+	Known      bool     // hash найден в каталоге стандартных кошельков
+	Family     string   // описание из каталога ("Unknown", если не найден)
+	Suspicious []string // найденные паттерны (WalletPattern*), только для неизвестного кода
+}
+
+// opcodePattern описывает последовательность байт TVM-опкодов.
+// Сканирование по байтам — эвристика: большинство опкодов кратны 8 битам.
+type opcodePattern struct {
+	name  string
+	match func(code []byte, i int) bool
+}
+
+var walletOpcodePatterns = []opcodePattern{
+	{
+		// DICTGET/DICTIGET/DICTUGET (F40A-F40F) и DICTSET/DICTDEL (F412-F417, F459-F45B)
+		name: WalletPatternDictLookup,
+		match: func(code []byte, i int) bool {
+			if code[i] != 0xF4 || i+1 >= len(code) {
+				return false
+			}
+			op := code[i+1]
+			return (op >= 0x0A && op <= 0x0F) || (op >= 0x12 && op <= 0x17) || (op >= 0x59 && op <= 0x5B)
+		},
+	},
+	{
+		// AND (B0), затем THROWIF/THROWIFNOT (F240-F2BF)
+		name: WalletPatternPauseFlag,
+		match: func(code []byte, i int) bool {
+			return code[i] == 0xB0 && i+2 < len(code) &&
+				code[i+1] == 0xF2 && code[i+2] >= 0x40 && code[i+2] <= 0xBF
+		},
+	},
+	{
+		// NOW (F823)
+		name: WalletPatternTimeCheck,
+		match: func(code []byte, i int) bool {
+			return code[i] == 0xF8 && i+1 < len(code) && code[i+1] == 0x23
+		},
+	},
+}
+
+// sdeqOpcode — SDEQ (C705), сравнение slice, которым FunC сравнивает адреса.
+var sdeqOpcode = []byte{0xC7, 0x05}
+
+// classifyWalletCode хэширует jetton_wallet_code и сверяет с каталогом.
+// Неизвестный код дополнительно сканируется на подозрительные опкоды.
+// Пока каталог пуст, код не считается неизвестным: сверять не с чем.
+func (d *Detector) classifyWalletCode(addr string, walletCode *cell.Cell) *WalletCodeInfo {
+	hash := hex.EncodeToString(walletCode.Hash())
+	d.mu.RLock()
+	desc, known := d.walletCodeHashes[hash]
+	checked := len(d.walletCodeHashes) > 0
+	d.mu.RUnlock()
+
+	info := &WalletCodeInfo{
+		Hash:    hash,
+		Checked: checked,
+		Family:  "Unknown",
+	}
+
+	if known {
+		info.Known = true
+		info.Family = desc
+		return info
+	}
+
+	info.Suspicious = inspectWalletCode(walletCode)
+	if !info.Checked {
+		return info
+	}
+
+	d.logger.Info("неизвестный код jetton-кошелька",
+		zap.String("address", addr),
+		zap.String("wallet_code_hash", info.Hash),
+		zap.Strings("suspicious", info.Suspicious),
+	)
+
+	return info
+}
+
+// inspectWalletCode ищет подозрительные паттерны во всём дереве ячеек кода.
+func inspectWalletCode(code *cell.Cell) []string {
+	found := make(map[string]bool)
+	senderChecks := 0
+
+	walkCells(code, func(c *cell.Cell) {
+		data := cellBytes(c)
+		for i := range data {
+			for _, p := range walletOpcodePatterns {
+				if p.match(data, i) {
+					found[p.name] = true
+				}
+			}
+		}
+		senderChecks += bytes.Count(data, sdeqOpcode)
+	})
+
+	if senderChecks > standardSenderChecks {
+		found[WalletPatternSenderChecks] = true
+	}
+
+	// Порядок фиксированный, чтобы причины были стабильны между запусками
+	suspicious := make([]string, 0, len(found))
+	for _, name := range []string{
+		WalletPatternDictLookup,
+		WalletPatternPauseFlag,
+		WalletPatternTimeCheck,
+		WalletPatternSenderChecks,
+	} {
+		if found[name] {
+			suspicious = append(suspicious, name)
+		}
+	}
+	return suspicious
+}
+
+// walkCells обходит дерево ячеек, посещая каждую уникальную ячейку один раз.
+func walkCells(root *cell.Cell, visit func(c *cell.Cell)) {
+	seen := make(map[string]bool)
+
+	var walk func(c *cell.Cell)
+	walk = func(c *cell.Cell) {
+		key := string(c.Hash())
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+
+		visit(c)
+
+		for i := 0; i < int(c.RefsNum()); i++ {
+			ref, err := c.PeekRef(i)
+			if err != nil {
+				continue
+			}
+			walk(ref)
+		}
+	}
+
+	walk(root)
+}
+
+// cellBytes возвращает биты данных ячейки (последний байт дополнен нулями).
+func cellBytes(c *cell.Cell) []byte {
+	data, err := c.BeginParse().LoadSlice(c.BitsSize())
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+// defaultJettonWalletCodeHashes возвращает hash кода стандартных jetton-кошельков.
+// Встроенных хэшей нет: каталог заполняется из конфига (detector.jetton_wallet_code_hashes)
+// hash кода кошельков ton-blockchain/token-contract и stablecoin-contract нужной сборки.
+func defaultJettonWalletCodeHashes() map[string]string {
+	return map[string]string{}
+}
+
+// AddWalletCodeHash добавляет hash кода jetton-кошелька в каталог в runtime.
+func (d *Detector) AddWalletCodeHash(hash, description string) {
+	d.mu.Lock()
+	d.walletCodeHashes[strings.ToLower(hash)] = description
+	d.mu.Unlock()
+
+	d.logger.Info("добавлен hash кода jetton-кошелька",
+		zap.String("hash", hash),
+		zap.String("description", description),
+	)
+}
@@ -0,0 +1,363 @@
+package holders
+
+import (
+	"context"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/yourname/hyper-sniper-indexer/internal/detector"
+	"github.com/yourname/hyper-sniper-indexer/pkg/ton"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultInterval  = 5 * time.Minute
+	defaultWindow    = 3 * time.Hour
+	defaultMaxTokens = 5000
+
+	// Сколько крупнейших держателей попадает в снимок
+	topHolders = 10
+)
+
+// Holder — держатель в снимке распределения.
+type Holder struct {
+	Wallet  string  `json:"wallet"`
+	Owner   string  `json:"owner,omitempty"` // известен по transfer_notification
+	Balance string  `json:"balance"`
+	Share   float64 `json:"share"` // % от supply
+}
+
+// Distribution — снимок распределения держателей jetton по наблюдаемым переводам.
+type Distribution struct {
+	Minter         string    `json:"minter"`
+	Symbol         string    `json:"symbol,omitempty"`
+	Supply         string    `json:"supply"`
+	Holders        int       `json:"holders"`          // кошельков с ненулевым балансом
+	TopHolderShare float64   `json:"top_holder_share"` // доля крупнейшего держателя, %
+	Top10Share     float64   `json:"top10_share"`      // доля top-10, %
+	Deployer       string    `json:"deployer,omitempty"`
+	DeployerWallet string    `json:"deployer_wallet,omitempty"`
+	DeployerShare  float64   `json:"deployer_share"`
+	Top            []Holder  `json:"top"`
+	Transfers      int       `json:"transfers"` // учтено internal_transfer
+	DetectedAt     time.Time `json:"detected_at"`
+	At             time.Time `json:"at"`
+}
+
+// WalletResolver вычисляет адрес jetton-кошелька владельца (реализуется detector.Detector).
+type WalletResolver interface {
+	WalletAddress(ctx context.Context, minter, owner string) (string, error)
+}
+
+// Notifier доставляет периодические снимки (реализуется notifier.Notifier).
+type Notifier interface {
+	NotifyHolders(ctx context.Context, d *Distribution)
+}
+
+// book — балансы кошельков одного jetton.
+type book struct {
+	minter         string
+	symbol         string
+	deployer       string
+	deployerWallet string
+	supply         *big.Int // supply при обнаружении плюс наблюдаемые mint
+	balances       map[string]*big.Int
+	owners         map[string]string
+	transfers      int
+	detectedAt     time.Time
+}
+
+// Tracker ведёт таблицу держателей недавно найденных jetton по internal_transfer и burn
+// из потока блоков и периодически отправляет снимки распределения.
+// Балансы до начала наблюдения неизвестны: списание с неизвестного кошелька не уводит баланс ниже нуля.
+type Tracker struct {
+	resolver  WalletResolver
+	notifier  Notifier
+	interval  time.Duration
+	window    time.Duration
+	maxTokens int
+	logger    *zap.Logger
+
+	mu    sync.Mutex
+	books map[string]*book
+}
+
+// New создаёт трекер держателей. Нулевые interval/window/maxTokens заменяются значениями по умолчанию.
+func New(resolver WalletResolver, ntf Notifier, interval, window time.Duration, maxTokens int, logger *zap.Logger) *Tracker {
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+	if window <= 0 {
+		window = defaultWindow
+	}
+	if maxTokens <= 0 {
+		maxTokens = defaultMaxTokens
+	}
+
+	return &Tracker{
+		resolver:  resolver,
+		notifier:  ntf,
+		interval:  interval,
+		window:    window,
+		maxTokens: maxTokens,
+		logger:    logger,
+		books:     make(map[string]*book),
+	}
+}
+
+// Window возвращает, сколько после обнаружения ведётся таблица держателей.
+func (t *Tracker) Window() time.Duration {
+	return t.window
+}
+
+// Track заводит таблицу держателей для найденного минтера.
+// При переполнении вытесняется самый старый минтер.
+func (t *Tracker) Track(meta *detector.Metadata, deployer string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, ok := t.books[meta.Address]; ok {
+		return
+	}
+	if len(t.books) >= t.maxTokens {
+		t.evictOldest()
+	}
+
+	supply, ok := new(big.Int).SetString(meta.TotalSupply, 10)
+	if !ok {
+		supply = new(big.Int)
+	}
+	detectedAt := meta.Timestamp
+	if detectedAt.IsZero() {
+		detectedAt = time.Now().UTC()
+	}
+
+	t.books[meta.Address] = &book{
+		minter:     meta.Address,
+		symbol:     meta.Symbol,
+		deployer:   deployer,
+		supply:     supply,
+		balances:   make(map[string]*big.Int),
+		owners:     make(map[string]string),
+		detectedAt: detectedAt,
+	}
+}
+
+// Apply учитывает операцию jetton: internal_transfer зачисляет на кошелёк получателя
+// и списывает с кошелька отправителя (mint от минтера увеличивает supply), burn списывает,
+// transfer_notification раскрывает владельца кошелька.
+func (t *Tracker) Apply(ev *ton.JettonEvent) {
+	op := ev.Event.Op
+	if op == nil || op.Aborted || ev.Wallet == "" {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	b, ok := t.books[ev.Minter]
+	if !ok {
+		return
+	}
+
+	switch op.Kind {
+	case ton.OpInternalTransfer:
+		amount, ok := new(big.Int).SetString(op.Amount, 10)
+		if !ok {
+			return
+		}
+		b.credit(ev.Wallet, amount)
+		if from := ev.Event.Deployer; from == b.minter {
+			b.growSupply()
+		} else {
+			b.debit(from, amount)
+		}
+		b.transfers++
+	case ton.OpBurn:
+		if amount, ok := new(big.Int).SetString(op.Amount, 10); ok {
+			b.debit(ev.Wallet, amount)
+			b.supply.Sub(b.supply, amount)
+			if b.supply.Sign() < 0 {
+				b.supply.SetInt64(0)
+			}
+		}
+	case ton.OpTransferNotification:
+		b.owners[ev.Wallet] = ev.Event.AccountAddress
+	}
+}
+
+// Snapshot возвращает текущее распределение держателей минтера.
+func (t *Tracker) Snapshot(ctx context.Context, minter string) (*Distribution, bool) {
+	t.mu.Lock()
+	b, ok := t.books[minter]
+	var deployer, wallet string
+	if ok {
+		deployer, wallet = b.deployer, b.deployerWallet
+	}
+	t.mu.Unlock()
+	if !ok {
+		return nil, false
+	}
+
+	// Кошелёк деплоера вычисляется один раз get_wallet_address, вне блокировки
+	if wallet == "" && deployer != "" && t.resolver != nil {
+		resolved, err := t.resolver.WalletAddress(ctx, minter, deployer)
+		if err != nil {
+			t.logger.Debug("не удалось вычислить кошелёк деплоера",
+				zap.String("minter", minter),
+				zap.Error(err),
+			)
+		}
+		wallet = resolved
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if b, ok = t.books[minter]; !ok {
+		return nil, false
+	}
+	if wallet != "" {
+		b.deployerWallet = wallet
+	}
+	return b.distribution(time.Now().UTC()), true
+}
+
+// Run отправляет снимки распределения каждые interval до отмены ctx.
+func (t *Tracker) Run(ctx context.Context) {
+	t.logger.Info("трекер держателей запущен",
+		zap.Duration("interval", t.interval),
+		zap.Duration("window", t.window),
+	)
+
+	ticker := time.NewTicker(t.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			t.SnapshotAll(ctx)
+		}
+	}
+}
+
+// SnapshotAll отправляет снимки по всем минтерам в окне наблюдения, у которых были переводы.
+func (t *Tracker) SnapshotAll(ctx context.Context) {
+	for _, minter := range t.active(time.Now()) {
+		if ctx.Err() != nil {
+			return
+		}
+		d, ok := t.Snapshot(ctx, minter)
+		if !ok || d.Transfers == 0 {
+			continue
+		}
+		if t.notifier != nil {
+			t.notifier.NotifyHolders(ctx, d)
+		}
+	}
+}
+
+// active возвращает минтеры в окне наблюдения и удаляет устаревшие.
+func (t *Tracker) active(now time.Time) []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	minters := make([]string, 0, len(t.books))
+	for addr, b := range t.books {
+		if now.Sub(b.detectedAt) > t.window {
+			delete(t.books, addr)
+			continue
+		}
+		minters = append(minters, addr)
+	}
+	return minters
+}
+
+// evictOldest удаляет самый давно найденный минтер. Вызывается под t.mu.
+func (t *Tracker) evictOldest() {
+	var oldest *book
+	for _, b := range t.books {
+		if oldest == nil || b.detectedAt.Before(oldest.detectedAt) {
+			oldest = b
+		}
+	}
+	if oldest != nil {
+		delete(t.books, oldest.minter)
+	}
+}
+
+func (b *book) credit(wallet string, amount *big.Int) {
+	balance, ok := b.balances[wallet]
+	if !ok {
+		balance = new(big.Int)
+		b.balances[wallet] = balance
+	}
+	balance.Add(balance, amount)
+}
+
+func (b *book) debit(wallet string, amount *big.Int) {
+	balance, ok := b.balances[wallet]
+	if !ok {
+		return
+	}
+	balance.Sub(balance, amount)
+	if balance.Sign() <= 0 {
+		delete(b.balances, wallet)
+	}
+}
+
+// growSupply поднимает supply до суммы балансов после mint.
+// Первичный mint обычно уже учтён в total_supply при обнаружении, поэтому supply растёт только на превышение.
+func (b *book) growSupply() {
+	total := new(big.Int)
+	for _, balance := range b.balances {
+		total.Add(total, balance)
+	}
+	if total.Cmp(b.supply) > 0 {
+		b.supply = total
+	}
+}
+
+// distribution собирает снимок. Вызывается под t.mu.
+func (b *book) distribution(now time.Time) *Distribution {
+	supply := b.supply.String()
+	d := &Distribution{
+		Minter:         b.minter,
+		Symbol:         b.symbol,
+		Supply:         supply,
+		Holders:        len(b.balances),
+		Deployer:       b.deployer,
+		DeployerWallet: b.deployerWallet,
+		Transfers:      b.transfers,
+		DetectedAt:     b.detectedAt,
+		At:             now,
+	}
+
+	wallets := make([]string, 0, len(b.balances))
+	for wallet := range b.balances {
+		wallets = append(wallets, wallet)
+	}
+	sort.Slice(wallets, func(i, j int) bool {
+		return b.balances[wallets[i]].Cmp(b.balances[wallets[j]]) > 0
+	})
+
+	for i, wallet := range wallets {
+		if i == topHolders {
+			break
+		}
+		balance := b.balances[wallet].String()
+		share := detector.SharePercent(balance, supply)
+		d.Top = append(d.Top, Holder{Wallet: wallet, Owner: b.owners[wallet], Balance: balance, Share: share})
+		d.Top10Share += share
+	}
+	if len(d.Top) > 0 {
+		d.TopHolderShare = d.Top[0].Share
+	}
+	if balance, ok := b.balances[b.deployerWallet]; ok {
+		d.DeployerShare = detector.SharePercent(balance.String(), supply)
+	}
+	return d
+}
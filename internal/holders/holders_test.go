@@ -0,0 +1,81 @@
+package holders
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/yourname/hyper-sniper-indexer/internal/detector"
+	"github.com/yourname/hyper-sniper-indexer/pkg/ton"
+	"go.uber.org/zap"
+)
+
+type resolverStub struct {
+	wallets map[string]string
+	calls   int
+}
+
+func (r *resolverStub) WalletAddress(_ context.Context, _, owner string) (string, error) {
+	r.calls++
+	return r.wallets[owner], nil
+}
+
+type notifierStub struct {
+	snapshots []*Distribution
+}
+
+func (n *notifierStub) NotifyHolders(_ context.Context, d *Distribution) {
+	n.snapshots = append(n.snapshots, d)
+}
+
+func transfer(minter, from, to, amount string) *ton.JettonEvent {
+	return &ton.JettonEvent{
+		Minter: minter,
+		Wallet: to,
+		Event: ton.Event{
+			AccountAddress: to,
+			Deployer:       from,
+			Op:             &ton.JettonOp{Kind: ton.OpInternalTransfer, Amount: amount},
+		},
+	}
+}
+
+func TestTrackerDistribution(t *testing.T) {
+	resolver := &resolverStub{wallets: map[string]string{"0:deployer": "0:w-deployer"}}
+	ntf := &notifierStub{}
+	trk := New(resolver, ntf, time.Minute, time.Hour, 0, zap.NewNop())
+
+	trk.Track(&detector.Metadata{Address: "0:minter", Symbol: "TST", TotalSupply: "1000", Timestamp: time.Now()}, "0:deployer")
+
+	// Весь supply деплоеру, затем раздача двум покупателям и burn у одного из них
+	trk.Apply(transfer("0:minter", "0:minter", "0:w-deployer", "1000"))
+	trk.Apply(transfer("0:minter", "0:w-deployer", "0:w-a", "300"))
+	trk.Apply(transfer("0:minter", "0:w-deployer", "0:w-b", "100"))
+	trk.Apply(&ton.JettonEvent{Minter: "0:minter", Wallet: "0:w-b", Event: ton.Event{
+		AccountAddress: "0:w-b",
+		Op:             &ton.JettonOp{Kind: ton.OpBurn, Amount: "100"},
+	}})
+	// Откатившийся перевод не учитывается
+	aborted := transfer("0:minter", "0:w-deployer", "0:w-c", "50")
+	aborted.Event.Op.Aborted = true
+	trk.Apply(aborted)
+
+	d, ok := trk.Snapshot(context.Background(), "0:minter")
+	if !ok {
+		t.Fatalf("minter is not tracked")
+	}
+	if d.Supply != "900" || d.Holders != 2 || d.Transfers != 3 {
+		t.Fatalf("unexpected totals: supply=%s holders=%d transfers=%d", d.Supply, d.Holders, d.Transfers)
+	}
+	if d.DeployerWallet != "0:w-deployer" || d.Top[0].Wallet != "0:w-deployer" {
+		t.Fatalf("unexpected deployer wallet or top holder: %+v", d)
+	}
+	if d.DeployerShare < 66.6 || d.DeployerShare > 66.7 || d.TopHolderShare != d.DeployerShare || d.Top10Share != 100 {
+		t.Fatalf("unexpected shares: top1=%.2f top10=%.2f deployer=%.2f", d.TopHolderShare, d.Top10Share, d.DeployerShare)
+	}
+
+	trk.SnapshotAll(context.Background())
+	if len(ntf.snapshots) != 1 || resolver.calls != 1 {
+		t.Fatalf("expected one snapshot and one wallet resolution, got %d/%d", len(ntf.snapshots), resolver.calls)
+	}
+}
@@ -3,6 +3,7 @@ package indexer
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/yourname/hyper-sniper-indexer/internal/config"
@@ -11,6 +12,9 @@ import (
 	"go.uber.org/zap"
 )
 
+// Сколько остановка ждёт воркеры, если shutdown_grace не задан
+const defaultShutdownGrace = 10 * time.Second
+
 // Service управляет жизненным циклом тон-индексера и обработчиком транзакций.
 type Service struct {
 	cfg       *config.Config
@@ -18,6 +22,7 @@ type Service struct {
 	processor *processor.Processor
 	logger    *zap.Logger
 	cancel    context.CancelFunc
+	wg        sync.WaitGroup
 }
 
 // NewService создаёт сервис индексатора.
@@ -45,24 +50,53 @@ func (s *Service) Start(ctx context.Context) error {
 
 	s.logger.Info("ton-indexer запущен", zap.String("network", s.cfg.App.Network))
 
-	go s.runCatchup(runCtx)
-	go s.runRealtime(runCtx)
+	s.spawn(runCtx, s.processor.RunPipeline)
+	s.spawn(runCtx, s.runCatchup)
+	s.spawn(runCtx, s.runRealtime)
+	s.spawn(runCtx, s.processor.RunRechecks)
+	s.spawn(runCtx, s.processor.RunCampaigns)
 	return nil
 }
 
-// Stop останавливает сервис.
+// spawn запускает фоновую задачу, которую дожидается Stop.
+func (s *Service) spawn(ctx context.Context, run func(context.Context)) {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		run(ctx)
+	}()
+}
+
+// Stop отменяет обработку (проверки и HTTP-запросы получают отменённый ctx)
+// и ждёт фоновые задачи не дольше shutdown_grace.
 func (s *Service) Stop() {
 	if s.cancel != nil {
 		s.cancel()
 	}
+
+	grace := config.DurationOr(s.cfg.Timeouts.ShutdownGrace, defaultShutdownGrace)
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(grace):
+		s.logger.Warn("фоновые задачи не завершились за grace-период, выходим",
+			zap.Duration("grace", grace),
+		)
+	}
 }
 
 func (s *Service) runRealtime(ctx context.Context) {
-	handler := func(event ton.Event) error {
+	handler := func(ctx context.Context, event ton.Event) error {
 		if s.processor == nil {
 			return fmt.Errorf("processor не инициализирован")
 		}
-		return s.processor.Handle(event)
+		return s.processor.Handle(ctx, event)
 	}
 
 	if err := s.client.Subscribe(ctx, handler); err != nil {
@@ -82,15 +116,14 @@ func (s *Service) runCatchup(ctx context.Context) {
 	since := time.Now().Add(-catchupDuration)
 	s.logger.Info("запуск catchup", zap.Time("since", since), zap.Duration("duration", catchupDuration))
 
-	handler := func(event ton.Event) error {
+	handler := func(ctx context.Context, event ton.Event) error {
 		if s.processor == nil {
 			return fmt.Errorf("processor не инициализирован")
 		}
-		return s.processor.Handle(event)
+		return s.processor.Handle(ctx, event)
 	}
 
 	if err := s.client.Catchup(ctx, since, handler); err != nil {
 		s.logger.Error("catchup завершился с ошибкой", zap.Error(err))
 	}
 }
-
@@ -0,0 +1,95 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/yourname/hyper-sniper-indexer/internal/detector"
+	"go.uber.org/zap"
+)
+
+const (
+	campaignStartedEvent  = "campaign_started"
+	campaignFinishedEvent = "campaign_finished"
+)
+
+// CampaignPayload — JSON о спам-кампании.
+type CampaignPayload struct {
+	Event string `json:"event"` // campaign_started / campaign_finished
+	*detector.Campaign
+	Unixtime int64 `json:"unixtime"`
+}
+
+// NotifyCampaign отправляет сводку спам-кампании: при обнаружении и после завершения со счётчиком.
+func (n *Notifier) NotifyCampaign(ctx context.Context, camp *detector.Campaign) {
+	n.consoleCampaign(camp)
+
+	if n.tgToken != "" && n.tgChatID != "" {
+		if err := n.sendTelegram(ctx, campaignText(camp)); err != nil {
+			n.logger.Warn("ошибка отправки в Telegram", zap.Error(err))
+		}
+	}
+
+	if n.webhookURL != "" {
+		event := campaignEventName(camp)
+		payload := CampaignPayload{Event: event, Campaign: camp, Unixtime: time.Now().Unix()}
+		if err := n.postWebhook(ctx, event, payload); err != nil {
+			n.logger.Warn("ошибка отправки в webhook", zap.Error(err))
+		}
+	}
+}
+
+func campaignEventName(camp *detector.Campaign) string {
+	if camp.Finished {
+		return campaignFinishedEvent
+	}
+	return campaignStartedEvent
+}
+
+// consoleCampaign выводит сводку кампании в консоль.
+func (n *Notifier) consoleCampaign(camp *detector.Campaign) {
+	yellow := color.New(color.FgHiYellow, color.Bold)
+	white := color.New(color.FgWhite)
+
+	fmt.Println()
+	yellow.Printf("  🚨 %s: %s = %s\n", campaignEventName(camp), camp.By, truncateHash(camp.Value))
+	white.Printf("  Деплоев: %d (подавлено %d)\n", camp.Count, camp.Suppressed)
+	fmt.Println()
+}
+
+// campaignText формирует текст для Telegram.
+func campaignText(camp *detector.Campaign) string {
+	title := "🚨 СПАМ-КАМПАНИЯ: уведомления о минтерах свёрнуты"
+	if camp.Finished {
+		title = "🚨 СПАМ-КАМПАНИЯ ЗАВЕРШЕНА"
+	}
+	symbols := "—"
+	if len(camp.Symbols) > 0 {
+		symbols = strings.Join(camp.Symbols, ", ")
+	}
+
+	return fmt.Sprintf(
+		"%s\n\n"+
+			"🔑 Признак: %s = %s\n"+
+			"📊 Деплоев: %d (подавлено %d)\n"+
+			"🏷 Символы: %s\n"+
+			"⏱ С %s по %s\n\n"+
+			"🔍 Пример: %s%s",
+		title,
+		camp.By, camp.Value,
+		camp.Count, camp.Suppressed,
+		symbols,
+		camp.FirstSeen.UTC().Format("15:04:05"), camp.LastSeen.UTC().Format("15:04:05"),
+		tonViewerBase, firstOf(camp.Sample),
+	)
+}
+
+func firstOf(list []string) string {
+	if len(list) == 0 {
+		return ""
+	}
+	return list[0]
+}
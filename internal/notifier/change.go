@@ -0,0 +1,105 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/yourname/hyper-sniper-indexer/internal/detector"
+	"go.uber.org/zap"
+)
+
+// ChangePayload — JSON об изменении состояния уже найденного минтера.
+type ChangePayload struct {
+	Event   string `json:"event"`
+	Address string `json:"address"`
+	Name    string `json:"name,omitempty"`
+	Symbol  string `json:"symbol,omitempty"`
+	Old     string `json:"old"`
+	New     string `json:"new"`
+
+	DetectedUnixtime int64 `json:"detected_unixtime"`
+	ChangedUnixtime  int64 `json:"changed_unixtime"`
+	SinceLaunchSec   int64 `json:"since_launch_sec"`
+
+	Links LinksInfo `json:"links"`
+}
+
+// NotifyChange отправляет изменение минтера (supply, админ, content).
+// Имя события webhook совпадает с типом изменения.
+func (n *Notifier) NotifyChange(ctx context.Context, change *detector.Change) {
+	n.consoleChange(change)
+
+	if n.tgToken != "" && n.tgChatID != "" {
+		if err := n.sendTelegram(ctx, changeText(change)); err != nil {
+			n.logger.Warn("ошибка отправки в Telegram", zap.Error(err))
+		}
+	}
+
+	if n.webhookURL != "" {
+		if err := n.postWebhook(ctx, string(change.Kind), buildChangePayload(change)); err != nil {
+			n.logger.Warn("ошибка отправки в webhook", zap.Error(err))
+		}
+	}
+}
+
+// consoleChange выводит изменение в консоль.
+func (n *Notifier) consoleChange(change *detector.Change) {
+	yellow := color.New(color.FgYellow, color.Bold)
+	white := color.New(color.FgWhite)
+
+	fmt.Println()
+	yellow.Printf("  🔄 %s: %s (%s)\n", change.Kind, change.Symbol, truncateHash(change.Address))
+	white.Printf("  %s -> %s\n", displayValue(change.Old), displayValue(change.New))
+	white.Printf("  После запуска: %s\n", change.SinceLaunch().Round(time.Second))
+	fmt.Println()
+}
+
+// changeText формирует текст для Telegram.
+func changeText(change *detector.Change) string {
+	return fmt.Sprintf(
+		"🔄 %s\n\n"+
+			"🪙 %s (%s)\n"+
+			"📍 Адрес: %s\n"+
+			"✏️ %s → %s\n"+
+			"⏱ После запуска: %s\n\n"+
+			"🔍 Tonviewer: %s%s",
+		change.Kind,
+		change.Name, change.Symbol,
+		change.Address,
+		displayValue(change.Old), displayValue(change.New),
+		change.SinceLaunch().Round(time.Second),
+		tonViewerBase, change.Address,
+	)
+}
+
+// buildChangePayload собирает JSON для webhook.
+func buildChangePayload(change *detector.Change) ChangePayload {
+	return ChangePayload{
+		Event:   string(change.Kind),
+		Address: change.Address,
+		Name:    change.Name,
+		Symbol:  change.Symbol,
+		Old:     change.Old,
+		New:     change.New,
+
+		DetectedUnixtime: change.DetectedAt.Unix(),
+		ChangedUnixtime:  change.ChangedAt.Unix(),
+		SinceLaunchSec:   int64(change.SinceLaunch().Seconds()),
+
+		Links: LinksInfo{
+			Tonviewer:   tonViewerBase + change.Address,
+			Tonscan:     tonscanBase + change.Address,
+			DexScreener: dexScreenerURL + change.Address,
+		},
+	}
+}
+
+// displayValue подставляет прочерк вместо пустого значения (например, админ после renounce).
+func displayValue(v string) string {
+	if v == "" {
+		return "—"
+	}
+	return v
+}
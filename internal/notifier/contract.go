@@ -0,0 +1,162 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/yourname/hyper-sniper-indexer/internal/detector"
+	"github.com/yourname/hyper-sniper-indexer/pkg/ton"
+	"go.uber.org/zap"
+)
+
+// ContractPayload — JSON для контрактов не-jetton типа (NFT, пул, лаунчпад).
+type ContractPayload struct {
+	Event        string `json:"event"`
+	ContractKind string `json:"contract_kind"`
+	Address      string `json:"address"`
+	CodeHash     string `json:"code_hash"`
+	Deployer     string `json:"deployer,omitempty"`
+	Workchain    int32  `json:"workchain"`
+	Seqno        uint32 `json:"seqno"`
+	TxLT         uint64 `json:"tx_lt,omitempty"`
+
+	Interfaces []string `json:"interfaces,omitempty"`
+
+	NFT       *NFTInfo       `json:"nft,omitempty"`
+	Pool      *PoolInfo      `json:"pool,omitempty"`
+	Launchpad *LaunchpadInfo `json:"launchpad,omitempty"`
+
+	Meta  MetaInfo  `json:"meta"`
+	Links LinksInfo `json:"links"`
+}
+
+type NFTInfo struct {
+	NextItemIndex string `json:"next_item_index"`
+	ContentURI    string `json:"content_uri,omitempty"`
+	Owner         string `json:"owner,omitempty"`
+}
+
+type PoolInfo struct {
+	DEX      string `json:"dex"`
+	Token0   string `json:"token0"`
+	Token1   string `json:"token1"`
+	Reserve0 string `json:"reserve0"`
+	Reserve1 string `json:"reserve1"`
+}
+
+type LaunchpadInfo struct {
+	Name string `json:"name"`
+}
+
+// NotifyContract отправляет уведомление о контракте, распознанном не jetton-детектором.
+func (n *Notifier) NotifyContract(ctx context.Context, cls *detector.Classification, event *ton.Event) {
+	n.consoleContract(cls)
+
+	if n.tgToken != "" && n.tgChatID != "" {
+		if err := n.sendTelegram(ctx, contractText(cls)); err != nil {
+			n.logger.Warn("ошибка отправки в Telegram", zap.Error(err))
+		}
+	}
+
+	if n.webhookURL != "" {
+		if err := n.postWebhook(ctx, "contract_deployed", buildContractPayload(cls, event)); err != nil {
+			n.logger.Warn("ошибка отправки в webhook", zap.Error(err))
+		}
+	}
+}
+
+// consoleContract выводит краткое сообщение о контракте в консоль.
+func (n *Notifier) consoleContract(cls *detector.Classification) {
+	cyan := color.New(color.FgCyan, color.Bold)
+	white := color.New(color.FgWhite)
+
+	fmt.Println()
+	cyan.Printf("  📦 Новый контракт: %s\n", cls.Kind)
+	white.Printf("  Адрес:    %s\n", cls.Address)
+	white.Printf("  CodeHash: %s\n", truncateHash(cls.CodeHash))
+	if details := contractDetails(cls); details != "" {
+		white.Printf("  %s\n", details)
+	}
+	fmt.Println()
+}
+
+// contractText формирует текст для Telegram.
+func contractText(cls *detector.Classification) string {
+	text := fmt.Sprintf(
+		"📦 %s\n\n"+
+			"📍 Адрес: %s\n",
+		cls.Kind,
+		cls.Address,
+	)
+	if details := contractDetails(cls); details != "" {
+		text += details + "\n"
+	}
+	return text + fmt.Sprintf("\n🔍 Tonviewer: %s%s", tonViewerBase, cls.Address)
+}
+
+// contractDetails описывает специфичные для типа поля одной строкой.
+func contractDetails(cls *detector.Classification) string {
+	switch {
+	case cls.NFT != nil:
+		return fmt.Sprintf("NFT: items=%s owner=%s", cls.NFT.NextItemIndex, truncateHash(cls.NFT.OwnerAddr))
+	case cls.Pool != nil:
+		return fmt.Sprintf("Пул %s: %s / %s", cls.Pool.DEX, truncateHash(cls.Pool.Token0), truncateHash(cls.Pool.Token1))
+	case cls.Launchpad != nil:
+		return "Лаунчпад: " + cls.Launchpad.Name
+	}
+	return ""
+}
+
+// buildContractPayload собирает JSON для webhook.
+func buildContractPayload(cls *detector.Classification, event *ton.Event) ContractPayload {
+	payload := ContractPayload{
+		Event:        "contract_deployed",
+		ContractKind: string(cls.Kind),
+		Address:      cls.Address,
+		CodeHash:     cls.CodeHash,
+		Interfaces:   cls.Interfaces,
+
+		Meta: MetaInfo{
+			IndexerUnixtime: time.Now().Unix(),
+		},
+
+		Links: LinksInfo{
+			Tonviewer:   tonViewerBase + cls.Address,
+			Tonscan:     tonscanBase + cls.Address,
+			DexScreener: dexScreenerURL + cls.Address,
+		},
+	}
+
+	if cls.NFT != nil {
+		payload.NFT = &NFTInfo{
+			NextItemIndex: cls.NFT.NextItemIndex,
+			ContentURI:    cls.NFT.ContentURI,
+			Owner:         cls.NFT.OwnerAddr,
+		}
+	}
+	if cls.Pool != nil {
+		payload.Pool = &PoolInfo{
+			DEX:      cls.Pool.DEX,
+			Token0:   cls.Pool.Token0,
+			Token1:   cls.Pool.Token1,
+			Reserve0: cls.Pool.Reserve0,
+			Reserve1: cls.Pool.Reserve1,
+		}
+	}
+	if cls.Launchpad != nil {
+		payload.Launchpad = &LaunchpadInfo{Name: cls.Launchpad.Name}
+	}
+
+	if event != nil {
+		payload.Deployer = event.Deployer
+		payload.Workchain = event.Workchain
+		payload.Seqno = event.Seqno
+		payload.TxLT = event.TxLT
+		payload.Meta.BlockUnixtime = event.BlockUnixtime
+		payload.Meta.LatencyMs = time.Since(event.Timestamp).Milliseconds()
+	}
+
+	return payload
+}
@@ -0,0 +1,46 @@
+package notifier
+
+import (
+	"context"
+
+	"github.com/fatih/color"
+	"github.com/yourname/hyper-sniper-indexer/internal/holders"
+	"go.uber.org/zap"
+)
+
+const holdersEventName = "holders_snapshot"
+
+// HoldersPayload — JSON периодического снимка держателей.
+type HoldersPayload struct {
+	Event string `json:"event"`
+	*holders.Distribution
+	Links LinksInfo `json:"links"`
+}
+
+// NotifyHolders отправляет снимок распределения держателей. Снимки периодические,
+// поэтому идут только в консоль и webhook, без Telegram.
+func (n *Notifier) NotifyHolders(ctx context.Context, d *holders.Distribution) {
+	n.consoleHolders(d)
+
+	if n.webhookURL != "" {
+		payload := HoldersPayload{
+			Event:        holdersEventName,
+			Distribution: d,
+			Links: LinksInfo{
+				Tonviewer:   tonViewerBase + d.Minter,
+				Tonscan:     tonscanBase + d.Minter,
+				DexScreener: dexScreenerURL + d.Minter,
+			},
+		}
+		if err := n.postWebhook(ctx, holdersEventName, payload); err != nil {
+			n.logger.Warn("ошибка отправки в webhook", zap.Error(err))
+		}
+	}
+}
+
+// consoleHolders выводит снимок в консоль одной строкой.
+func (n *Notifier) consoleHolders(d *holders.Distribution) {
+	white := color.New(color.FgWhite)
+	white.Printf("  👥 %s (%s): держателей %d, top-1 %.1f%%, top-10 %.1f%%, деплоер %.1f%%\n",
+		d.Symbol, truncateHash(d.Minter), d.Holders, d.TopHolderShare, d.Top10Share, d.DeployerShare)
+}
@@ -0,0 +1,165 @@
+package notifier
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/yourname/hyper-sniper-indexer/pkg/ton"
+	"go.uber.org/zap"
+)
+
+// JettonOpPayload — JSON об операции недавно найденного jetton.
+type JettonOpPayload struct {
+	Event   string `json:"event"`
+	Minter  string `json:"minter"`
+	Wallet  string `json:"wallet,omitempty"` // кошелёк, на котором прошла операция
+	Account string `json:"account"`          // адрес транзакции
+	Op      string `json:"op"`
+	Opcode  string `json:"opcode"`
+	QueryID uint64 `json:"query_id"`
+
+	Destination     string `json:"destination,omitempty"`
+	Amount          string `json:"amount,omitempty"`
+	From            string `json:"from,omitempty"`
+	ResponseAddress string `json:"response_address,omitempty"`
+	ContentBOC      string `json:"content_boc,omitempty"`
+	ContentURI      string `json:"content_uri,omitempty"`
+
+	Initial bool `json:"initial"` // первый mint после деплоя
+	Aborted bool `json:"aborted"` // транзакция откатилась
+
+	Sender   string `json:"sender,omitempty"`
+	Seqno    uint32 `json:"seqno"`
+	TxHash   string `json:"tx_hash,omitempty"`
+	TxLT     uint64 `json:"tx_lt,omitempty"`
+	Unixtime int64  `json:"unixtime"`
+
+	Links LinksInfo `json:"links"`
+}
+
+type InitialMintInfo struct {
+	Recipient string `json:"recipient"`
+	Amount    string `json:"amount"`
+	QueryID   uint64 `json:"query_id"`
+}
+
+// NotifyJettonOp отправляет операцию jetton. Имя события webhook — "minter_<op>"
+// для операций минтера и "jetton_<op>" для операций кошельков.
+func (n *Notifier) NotifyJettonOp(ctx context.Context, ev *ton.JettonEvent) {
+	if ev.Event.Op == nil {
+		return
+	}
+	n.consoleJettonOp(ev)
+
+	// В Telegram — только первичный mint, остальное идёт в webhook
+	if ev.Initial && n.tgToken != "" && n.tgChatID != "" {
+		if err := n.sendTelegram(ctx, initialMintText(ev)); err != nil {
+			n.logger.Warn("ошибка отправки в Telegram", zap.Error(err))
+		}
+	}
+
+	if n.webhookURL != "" {
+		payload := buildJettonOpPayload(ev)
+		if err := n.postWebhook(ctx, payload.Event, payload); err != nil {
+			n.logger.Warn("ошибка отправки в webhook", zap.Error(err))
+		}
+	}
+}
+
+// consoleJettonOp выводит операцию jetton в консоль.
+func (n *Notifier) consoleJettonOp(ev *ton.JettonEvent) {
+	cyan := color.New(color.FgCyan, color.Bold)
+	white := color.New(color.FgWhite)
+
+	op := ev.Event.Op
+	title := string(op.Kind)
+	if ev.Initial {
+		title = "первичный mint"
+	}
+	if op.Aborted {
+		title += " (откат)"
+	}
+
+	fmt.Println()
+	cyan.Printf("  🪙 %s: %s\n", title, truncateHash(ev.Minter))
+	if ev.Wallet != "" {
+		white.Printf("  Кошелёк:  %s\n", ev.Wallet)
+	}
+	if op.From != "" && op.Kind.IsWalletOp() {
+		white.Printf("  От:       %s\n", op.From)
+	}
+	if op.Destination != "" {
+		white.Printf("  Кому:     %s\n", op.Destination)
+	}
+	if op.Amount != "" {
+		white.Printf("  Сумма:    %s\n", op.Amount)
+	}
+	fmt.Println()
+}
+
+// initialMintText формирует текст для Telegram.
+func initialMintText(ev *ton.JettonEvent) string {
+	return fmt.Sprintf(
+		"🪙 ПЕРВИЧНЫЙ MINT\n\n"+
+			"📍 Минтер: %s\n"+
+			"👤 Получатель: %s\n"+
+			"💰 Сумма: %s\n\n"+
+			"🔍 Tonviewer: %s%s",
+		ev.Minter,
+		ev.Event.Op.Destination,
+		ev.Event.Op.Amount,
+		tonViewerBase, ev.Minter,
+	)
+}
+
+// buildJettonOpPayload собирает JSON для webhook.
+func buildJettonOpPayload(ev *ton.JettonEvent) JettonOpPayload {
+	event := &ev.Event
+	op := event.Op
+
+	name := "minter_" + string(op.Kind)
+	if op.Kind.IsWalletOp() {
+		name = "jetton_" + string(op.Kind)
+	}
+
+	payload := JettonOpPayload{
+		Event:   name,
+		Minter:  ev.Minter,
+		Wallet:  ev.Wallet,
+		Account: event.AccountAddress,
+		Op:      string(op.Kind),
+		Opcode:  fmt.Sprintf("0x%08x", op.Opcode),
+		QueryID: op.QueryID,
+
+		Destination:     op.Destination,
+		Amount:          op.Amount,
+		From:            op.From,
+		ResponseAddress: op.ResponseAddress,
+		ContentURI:      op.ContentURI,
+
+		Initial: ev.Initial,
+		Aborted: op.Aborted,
+
+		Sender:   event.Deployer,
+		Seqno:    event.Seqno,
+		TxHash:   event.TxHash,
+		TxLT:     event.TxLT,
+		Unixtime: event.Timestamp.Unix(),
+
+		Links: LinksInfo{
+			Tonviewer:   tonViewerBase + ev.Minter,
+			Tonscan:     tonscanBase + ev.Minter,
+			DexScreener: dexScreenerURL + ev.Minter,
+		},
+	}
+	if len(op.Content) > 0 {
+		payload.ContentBOC = hex.EncodeToString(op.Content)
+	}
+	if payload.Unixtime <= 0 {
+		payload.Unixtime = time.Now().Unix()
+	}
+	return payload
+}
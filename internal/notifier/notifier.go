@@ -27,6 +27,11 @@ type Notifier struct {
 	tgToken    string
 	tgChatID   string
 	webhookURL string
+
+	// Отдельный маршрут для списка наблюдения (пусто — основной чат/webhook)
+	watchChatID     string
+	watchWebhookURL string
+
 	logger     *zap.Logger
 	httpClient *http.Client
 }
@@ -37,11 +42,20 @@ func New(cfg *config.Config, logger *zap.Logger) *Notifier {
 		tgToken:    cfg.Notifier.TgBotToken,
 		tgChatID:   cfg.Notifier.TgChatID,
 		webhookURL: cfg.Notifier.WebhookURL,
+
+		watchChatID:     cfg.Notifier.WatchTgChatID,
+		watchWebhookURL: cfg.Notifier.WatchWebhookURL,
+
 		logger:     logger,
-		httpClient: &http.Client{Timeout: 5 * time.Second},
+		httpClient: &http.Client{Timeout: httpTimeout(cfg)},
 	}
 }
 
+// httpTimeout — верхняя граница одного HTTP-запроса; дедлайн ctx вызывающего может быть короче.
+func httpTimeout(cfg *config.Config) time.Duration {
+	return config.DurationOr(cfg.Timeouts.Notify, 5*time.Second)
+}
+
 // Notify отправляет уведомление (обратная совместимость).
 func (n *Notifier) Notify(ctx context.Context, meta *detector.Metadata) {
 	n.NotifyWithEvent(ctx, meta, nil)
@@ -86,6 +100,9 @@ func (n *Notifier) console(meta *detector.Metadata) {
 
 	white.Printf("  Адрес:    %s\n", meta.Address)
 	cyan.Printf("  Тип:      %s\n", meta.MinterType)
+	if meta.Standard != "" && meta.Standard != detector.StandardTEP74 {
+		cyan.Printf("  Стандарт: %s\n", meta.Standard)
+	}
 
 	// Статус верификации
 	if meta.VerifiedByInterface && meta.KnownCodeHash {
@@ -98,11 +115,45 @@ func (n *Notifier) console(meta *detector.Metadata) {
 		red.Printf("  Статус:   ❓ Неизвестный\n")
 	}
 
+	if meta.WalletAddressChecked && !meta.WalletAddressMatch {
+		red.Printf("  Wallet:   ❌ get_wallet_address не совпадает с jetton_wallet_code\n")
+	}
+	if meta.Impersonation != nil {
+		red.Printf("  Подделка: 🎭 похож на %s (%s, %s)\n", meta.Impersonation.Symbol, meta.Impersonation.Field, meta.Impersonation.Method)
+	}
+	if d := meta.Deployer; d != nil {
+		if d.List == detector.DeployerAllowed {
+			green.Printf("  Деплоер:  ⭐ доверенный (%s)\n", truncateHash(d.Address))
+		}
+		if d.Flagged > 0 {
+			red.Printf("  Деплоер:  🔁 уже выпускал помеченные токены: %d (всего %d, за 24ч %d)\n", d.Flagged, d.Total, d.Last24h)
+		} else if d.Total > 1 {
+			white.Printf("  Деплоер:  токенов всего %d, за 24ч %d\n", d.Total, d.Last24h)
+		}
+	}
+	if meta.LateVerified {
+		yellow.Printf("  Проверка: ⏳ верифицирован повторно (late_verified)\n")
+	}
+
 	white.Printf("  CodeHash: %s\n", truncateHash(meta.CodeHash))
+	if meta.CodeFamily != "" {
+		white.Printf("  Семейство: %s (сходство %.0f%%)\n", meta.CodeFamily, meta.CodeSimilarity*100)
+	}
+
+	if wc := meta.WalletCode; wc != nil && !wc.Known && (wc.Checked || len(wc.Suspicious) > 0) {
+		red.Printf("  Кошелёк:  ⚠️ неизвестный код %s %v\n", truncateHash(wc.Hash), wc.Suspicious)
+	}
+
+	if meta.RiskScore > 0 {
+		red.Printf("  Риск:     %d/100 %v\n", meta.RiskScore, meta.RiskReasons)
+	}
 
 	if meta.TotalSupply != "" {
 		white.Printf("  Supply:   %s\n", meta.TotalSupply)
 	}
+	if op := meta.InitialMint; op != nil {
+		white.Printf("  Mint:     %s -> %s\n", op.Amount, truncateHash(op.Destination))
+	}
 	if meta.AdminAddr != "" {
 		white.Printf("  Admin:    %s\n", truncateHash(meta.AdminAddr))
 	}
@@ -147,6 +198,7 @@ func (n *Notifier) telegram(ctx context.Context, meta *detector.Metadata) error
 				"📍 Адрес: %s\n"+
 				"🔧 Тип: %s\n"+
 				"📊 Статус: %s\n"+
+				"⚠️ Риск: %d/100\n"+
 				"⚡ Latency: %d ms\n\n"+
 				"🔍 Tonviewer: %s%s\n"+
 				"🔍 Tonscan: %s%s\n\n"+
@@ -156,6 +208,7 @@ func (n *Notifier) telegram(ctx context.Context, meta *detector.Metadata) error
 			meta.Address,
 			meta.MinterType,
 			status,
+			meta.RiskScore,
 			meta.DetectionLatencyMs,
 			tonViewerBase, meta.Address,
 			tonscanBase, meta.Address,
@@ -167,6 +220,7 @@ func (n *Notifier) telegram(ctx context.Context, meta *detector.Metadata) error
 				"📍 Адрес: %s\n"+
 				"🔧 Тип: %s\n"+
 				"📊 Статус: %s\n"+
+				"⚠️ Риск: %d/100\n"+
 				"⚡ Latency: %d ms\n\n"+
 				"🔍 Tonviewer: %s%s\n"+
 				"🔍 Tonscan: %s%s\n\n"+
@@ -174,6 +228,7 @@ func (n *Notifier) telegram(ctx context.Context, meta *detector.Metadata) error
 			meta.Address,
 			meta.MinterType,
 			status,
+			meta.RiskScore,
 			meta.DetectionLatencyMs,
 			tonViewerBase, meta.Address,
 			tonscanBase, meta.Address,
@@ -181,9 +236,29 @@ func (n *Notifier) telegram(ctx context.Context, meta *detector.Metadata) error
 		)
 	}
 
+	if d := meta.Deployer; d != nil && d.Flagged > 0 {
+		text = fmt.Sprintf("🔁 Деплоер уже выпускал помеченные токены: %d (за 24ч: %d)\n\n", d.Flagged, d.Last24h) + text
+	}
+	if meta.Deployer != nil && meta.Deployer.List == detector.DeployerAllowed {
+		text = "⭐ ДОВЕРЕННЫЙ ДЕПЛОЕР\n\n" + text
+	}
+	if meta.Impersonation != nil {
+		text = fmt.Sprintf("🎭 ПОДДЕЛКА под %s (%s)\n\n", meta.Impersonation.Symbol, meta.Impersonation.Method) + text
+	}
+
+	return n.sendTelegram(ctx, text)
+}
+
+// sendTelegram отправляет текстовое сообщение в основной чат Telegram.
+func (n *Notifier) sendTelegram(ctx context.Context, text string) error {
+	return n.sendTelegramTo(ctx, n.tgChatID, text)
+}
+
+// sendTelegramTo отправляет текстовое сообщение в чат chatID.
+func (n *Notifier) sendTelegramTo(ctx context.Context, chatID, text string) error {
 	apiURL := fmt.Sprintf("https://api.telegram.org/bot%s/sendMessage", n.tgToken)
 	data := url.Values{}
-	data.Set("chat_id", n.tgChatID)
+	data.Set("chat_id", chatID)
 	data.Set("text", text)
 	data.Set("disable_web_page_preview", "true")
 
@@ -210,18 +285,29 @@ func (n *Notifier) telegram(ctx context.Context, meta *detector.Metadata) error
 // WebhookPayload структура JSON для торгового бота (расширенная версия).
 type WebhookPayload struct {
 	Event         string `json:"event"`
+	ContractKind  string `json:"contract_kind"`
 	MinterAddress string `json:"minter_address"`
+	Deployer      string `json:"deployer,omitempty"`
 	Workchain     int32  `json:"workchain"`
 	Seqno         uint32 `json:"seqno"`
 	TxHash        string `json:"tx_hash,omitempty"`
 	TxLT          uint64 `json:"tx_lt,omitempty"`
 	CodeHash      string `json:"code_hash"`
 
+	Interfaces []string `json:"interfaces,omitempty"`
+
 	Jetton JettonInfo `json:"jetton"`
 	Admin  AdminInfo  `json:"admin"`
 	Flags  FlagsInfo  `json:"flags"`
-	Meta   MetaInfo   `json:"meta"`
-	Links  LinksInfo  `json:"links"`
+	Risk   RiskInfo   `json:"risk"`
+	Wallet WalletInfo `json:"wallet_code"`
+	Family FamilyInfo `json:"code_family"`
+
+	Impersonation *ImpersonationInfo `json:"impersonation,omitempty"`
+	DeployerStats *DeployerInfo      `json:"deployer_stats,omitempty"`
+
+	Meta  MetaInfo  `json:"meta"`
+	Links LinksInfo `json:"links"`
 }
 
 type JettonInfo struct {
@@ -230,17 +316,67 @@ type JettonInfo struct {
 	Decimals    int    `json:"decimals"`
 	TotalSupply string `json:"total_supply"`
 	ContentURI  string `json:"content_uri,omitempty"`
+	Standard    string `json:"standard"`
+
+	Mintless    *MintlessInfo    `json:"mintless,omitempty"`
+	InitialMint *InitialMintInfo `json:"initial_mint,omitempty"` // mint в сообщении деплоя
+}
+
+type MintlessInfo struct {
+	MerkleRoot          string `json:"merkle_root"`
+	CustomPayloadAPIURI string `json:"custom_payload_api_uri,omitempty"`
 }
 
 type AdminInfo struct {
-	Address    string `json:"address"`
-	IsContract bool   `json:"is_contract"`
+	Address     string `json:"address"`
+	IsContract  bool   `json:"is_contract"`
+	NextAddress string `json:"next_address,omitempty"` // governed: ожидающий передачи админ
 }
 
 type FlagsInfo struct {
-	Mintable            bool `json:"mintable"`
-	VerifiedByInterface bool `json:"verified_by_interface"`
-	KnownCodeHash       bool `json:"known_code_hash"`
+	Mintable             bool `json:"mintable"`
+	VerifiedByInterface  bool `json:"verified_by_interface"`
+	KnownCodeHash        bool `json:"known_code_hash"`
+	WalletAddressChecked bool `json:"wallet_address_checked"`
+	WalletAddressMatch   bool `json:"wallet_address_match"`
+	LateVerified         bool `json:"late_verified"`
+	Governed             bool `json:"governed"`
+	Priority             bool `json:"priority"` // деплоер в allow-листе
+}
+
+type RiskInfo struct {
+	Score   int      `json:"score"`
+	Reasons []string `json:"reasons"`
+}
+
+type WalletInfo struct {
+	Hash       string   `json:"hash,omitempty"`
+	Checked    bool     `json:"checked"` // сверен с каталогом стандартных кошельков; иначе known не значим
+	Known      bool     `json:"known"`
+	Family     string   `json:"family,omitempty"`
+	Suspicious []string `json:"suspicious,omitempty"`
+}
+
+type ImpersonationInfo struct {
+	Symbol          string `json:"symbol"`
+	CanonicalMinter string `json:"canonical_minter"`
+	Field           string `json:"field"`
+	Method          string `json:"method"`
+	Distance        int    `json:"distance"`
+}
+
+type DeployerInfo struct {
+	Total             int      `json:"total"`
+	Last24h           int      `json:"last_24h"`
+	Flagged           int      `json:"flagged_before"`
+	FlaggedMinters    []string `json:"flagged_minters,omitempty"`
+	FirstSeenUnixtime int64    `json:"first_seen_unixtime"`
+	List              string   `json:"list,omitempty"`
+}
+
+type FamilyInfo struct {
+	Nearest    string  `json:"nearest,omitempty"`
+	Similarity float64 `json:"similarity"`
 }
 
 type MetaInfo struct {
@@ -260,6 +396,7 @@ type LinksInfo struct {
 func (n *Notifier) webhookExtended(ctx context.Context, meta *detector.Metadata, event *ton.Event) error {
 	payload := WebhookPayload{
 		Event:         "jetton_minter_deployed",
+		ContractKind:  string(detector.KindJettonMinter),
 		MinterAddress: meta.Address,
 		CodeHash:      meta.CodeHash,
 
@@ -269,17 +406,28 @@ func (n *Notifier) webhookExtended(ctx context.Context, meta *detector.Metadata,
 			Decimals:    meta.Decimals,
 			TotalSupply: meta.TotalSupply,
 			ContentURI:  meta.ContentURI,
+			Standard:    meta.Standard,
 		},
 
 		Admin: AdminInfo{
-			Address:    meta.AdminAddr,
-			IsContract: len(meta.AdminAddr) > 0 && meta.AdminAddr[0] != 'E', // упрощённая проверка
+			Address:     meta.AdminAddr,
+			IsContract:  len(meta.AdminAddr) > 0 && meta.AdminAddr[0] != 'E', // упрощённая проверка
+			NextAddress: meta.NextAdminAddr,
 		},
 
 		Flags: FlagsInfo{
-			Mintable:            meta.Mintable,
-			VerifiedByInterface: meta.VerifiedByInterface,
-			KnownCodeHash:       meta.KnownCodeHash,
+			Mintable:             meta.Mintable,
+			VerifiedByInterface:  meta.VerifiedByInterface,
+			KnownCodeHash:        meta.KnownCodeHash,
+			WalletAddressChecked: meta.WalletAddressChecked,
+			WalletAddressMatch:   meta.WalletAddressMatch,
+			LateVerified:         meta.LateVerified,
+			Governed:             meta.Governed,
+		},
+
+		Risk: RiskInfo{
+			Score:   meta.RiskScore,
+			Reasons: meta.RiskReasons,
 		},
 
 		Meta: MetaInfo{
@@ -295,26 +443,89 @@ func (n *Notifier) webhookExtended(ctx context.Context, meta *detector.Metadata,
 		},
 	}
 
+	payload.Interfaces = meta.Interfaces
+	if meta.Mintless != nil {
+		payload.Jetton.Mintless = &MintlessInfo{
+			MerkleRoot:          meta.Mintless.MerkleRoot,
+			CustomPayloadAPIURI: meta.Mintless.CustomPayloadAPIURI,
+		}
+	}
+	if op := meta.InitialMint; op != nil {
+		payload.Jetton.InitialMint = &InitialMintInfo{
+			Recipient: op.Destination,
+			Amount:    op.Amount,
+			QueryID:   op.QueryID,
+		}
+	}
+	if m := meta.Impersonation; m != nil {
+		payload.Impersonation = &ImpersonationInfo{
+			Symbol:          m.Symbol,
+			CanonicalMinter: m.CanonicalMinter,
+			Field:           m.Field,
+			Method:          m.Method,
+			Distance:        m.Distance,
+		}
+	}
+	if d := meta.Deployer; d != nil {
+		payload.DeployerStats = &DeployerInfo{
+			Total:             d.Total,
+			Last24h:           d.Last24h,
+			Flagged:           d.Flagged,
+			FlaggedMinters:    d.FlaggedMinters,
+			FirstSeenUnixtime: d.FirstSeen.Unix(),
+			List:              d.List,
+		}
+		payload.Flags.Priority = d.List == detector.DeployerAllowed
+	}
+	payload.Family = FamilyInfo{
+		Nearest:    meta.CodeFamily,
+		Similarity: meta.CodeSimilarity,
+	}
+
+	if meta.WalletCode != nil {
+		payload.Wallet = WalletInfo{
+			Hash:       meta.WalletCode.Hash,
+			Checked:    meta.WalletCode.Checked,
+			Known:      meta.WalletCode.Known,
+			Family:     meta.WalletCode.Family,
+			Suspicious: meta.WalletCode.Suspicious,
+		}
+	}
+
 	// Добавляем данные из события если есть
 	if event != nil {
 		payload.Workchain = event.Workchain
 		payload.Seqno = event.Seqno
 		payload.TxHash = event.TxHash
 		payload.TxLT = event.TxLT
+		payload.Deployer = event.Deployer
 		payload.Meta.BlockUnixtime = event.BlockUnixtime
 	}
 
+	return n.postWebhook(ctx, payload.Event, payload)
+}
+
+// postWebhook отправляет JSON в основной webhook с заголовком типа события.
+func (n *Notifier) postWebhook(ctx context.Context, eventName string, payload any) error {
+	return n.postWebhookTo(ctx, n.webhookURL, eventName, "", payload)
+}
+
+// postWebhookTo отправляет JSON на webhookURL. priority (если задан) уходит в X-HyperSniper-Priority.
+func (n *Notifier) postWebhookTo(ctx context.Context, webhookURL, eventName, priority string, payload any) error {
 	b, err := json.Marshal(payload)
 	if err != nil {
 		return err
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.webhookURL, bytes.NewBuffer(b))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewBuffer(b))
 	if err != nil {
 		return err
 	}
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("X-HyperSniper-Event", "jetton_minter_deployed")
+	req.Header.Set("X-HyperSniper-Event", eventName)
+	if priority != "" {
+		req.Header.Set("X-HyperSniper-Priority", priority)
+	}
 
 	resp, err := n.httpClient.Do(req)
 	if err != nil {
@@ -0,0 +1,172 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/yourname/hyper-sniper-indexer/internal/detector"
+	"go.uber.org/zap"
+)
+
+// PoolEventPayload — JSON о пуле нового jetton: создание, ликвидность, сжигание и блокировка LP.
+type PoolEventPayload struct {
+	Event  string `json:"event"` // pool_created / liquidity_added / lp_burned / lp_locked
+	Pool   string `json:"pool"`
+	Minter string `json:"minter"`
+	PoolInfo
+
+	JettonIndex int    `json:"jetton_index"`
+	Quote       string `json:"quote,omitempty"`
+	Price       string `json:"price_ton,omitempty"`
+
+	Provider string `json:"provider,omitempty"`
+	Amount0  string `json:"amount0,omitempty"`
+	Amount1  string `json:"amount1,omitempty"`
+	First    bool   `json:"first,omitempty"`
+
+	LP *LPInfo `json:"lp,omitempty"`
+
+	Unixtime int64     `json:"unixtime"`
+	Links    LinksInfo `json:"links"`
+}
+
+type LPInfo struct {
+	Owner   string  `json:"owner,omitempty"`
+	Wallet  string  `json:"wallet"`
+	Amount  string  `json:"amount"`
+	Supply  string  `json:"supply,omitempty"`
+	Percent float64 `json:"percent"`
+	Locker  string  `json:"locker,omitempty"`
+}
+
+// NotifyPool отправляет событие пула. В Telegram — всё, кроме повторных liquidity_added.
+func (n *Notifier) NotifyPool(ctx context.Context, ev *detector.PoolEvent) {
+	n.consolePool(ev)
+
+	if (ev.Kind != detector.LiquidityAdded || ev.First) && n.tgToken != "" && n.tgChatID != "" {
+		if err := n.sendTelegram(ctx, poolText(ev)); err != nil {
+			n.logger.Warn("ошибка отправки в Telegram", zap.Error(err))
+		}
+	}
+
+	if n.webhookURL != "" {
+		if err := n.postWebhook(ctx, ev.Kind, buildPoolPayload(ev)); err != nil {
+			n.logger.Warn("ошибка отправки в webhook", zap.Error(err))
+		}
+	}
+}
+
+// consolePool выводит событие пула в консоль.
+func (n *Notifier) consolePool(ev *detector.PoolEvent) {
+	blue := color.New(color.FgHiBlue, color.Bold)
+	white := color.New(color.FgWhite)
+
+	fmt.Println()
+	blue.Printf("  💧 %s (%s): %s\n", ev.Kind, ev.DEX, truncateHash(ev.Pool))
+	white.Printf("  Jetton:   %s\n", ev.Minter)
+	if ev.LPAmount != "" {
+		white.Printf("  LP:       %s (%.2f%%) %s\n", ev.LPAmount, ev.LPPercent, ev.Locker)
+		fmt.Println()
+		return
+	}
+	white.Printf("  Резервы:  %s / %s\n", ev.Reserve0, ev.Reserve1)
+	if ev.Price != "" {
+		white.Printf("  Цена:     %s TON\n", ev.Price)
+	}
+	fmt.Println()
+}
+
+// poolText формирует текст для Telegram.
+func poolText(ev *detector.PoolEvent) string {
+	switch ev.Kind {
+	case detector.LPBurned, detector.LPLocked:
+		return lpText(ev)
+	}
+
+	title := "💧 СОЗДАН ПУЛ"
+	if ev.Kind == detector.LiquidityAdded {
+		title = "💧 ПЕРВАЯ ЛИКВИДНОСТЬ"
+	}
+	price := "—"
+	if ev.Price != "" {
+		price = ev.Price + " TON"
+	}
+
+	return fmt.Sprintf(
+		"%s (%s)\n\n"+
+			"🪙 Jetton: %s\n"+
+			"📍 Пул: %s\n"+
+			"💰 Резервы: %s / %s\n"+
+			"📈 Цена: %s\n\n"+
+			"🔍 Tonviewer: %s%s",
+		title, ev.DEX,
+		ev.Minter,
+		ev.Pool,
+		ev.Reserve0, ev.Reserve1,
+		price,
+		tonViewerBase, ev.Pool,
+	)
+}
+
+// lpText формирует текст для Telegram о сжигании или блокировке LP.
+func lpText(ev *detector.PoolEvent) string {
+	title := "🔥 LP СОЖЖЕНЫ"
+	if ev.Kind == detector.LPLocked {
+		title = "🔒 LP ЗАБЛОКИРОВАНЫ: " + ev.Locker
+	}
+
+	return fmt.Sprintf(
+		"%s (%s)\n\n"+
+			"🪙 Jetton: %s\n"+
+			"📍 Пул: %s\n"+
+			"📊 Доля LP: %.2f%% (%s)\n\n"+
+			"🔍 Tonviewer: %s%s",
+		title, ev.DEX,
+		ev.Minter,
+		ev.Pool,
+		ev.LPPercent, ev.LPAmount,
+		tonViewerBase, ev.Pool,
+	)
+}
+
+// buildPoolPayload собирает JSON для webhook.
+func buildPoolPayload(ev *detector.PoolEvent) PoolEventPayload {
+	payload := PoolEventPayload{
+		Event:  ev.Kind,
+		Pool:   ev.Pool,
+		Minter: ev.Minter,
+		PoolInfo: PoolInfo{
+			DEX:      ev.DEX,
+			Token0:   ev.Token0,
+			Token1:   ev.Token1,
+			Reserve0: ev.Reserve0,
+			Reserve1: ev.Reserve1,
+		},
+		JettonIndex: ev.JettonIndex,
+		Quote:       ev.Quote,
+		Price:       ev.Price,
+		Provider:    ev.Provider,
+		Amount0:     ev.Amount0,
+		Amount1:     ev.Amount1,
+		First:       ev.First,
+		Unixtime:    time.Now().Unix(),
+		Links: LinksInfo{
+			Tonviewer:   tonViewerBase + ev.Pool,
+			Tonscan:     tonscanBase + ev.Pool,
+			DexScreener: dexScreenerURL + ev.Minter,
+		},
+	}
+	if ev.LPAmount != "" {
+		payload.LP = &LPInfo{
+			Owner:   ev.LPOwner,
+			Wallet:  ev.LPWallet,
+			Amount:  ev.LPAmount,
+			Supply:  ev.LPSupply,
+			Percent: ev.LPPercent,
+			Locker:  ev.Locker,
+		}
+	}
+	return payload
+}
@@ -0,0 +1,110 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/yourname/hyper-sniper-indexer/internal/rules"
+	"github.com/yourname/hyper-sniper-indexer/pkg/ton"
+	"go.uber.org/zap"
+)
+
+const ruleEventName = "rule_match"
+
+// RulePayload — JSON о деплое, совпавшем с правилом из конфига.
+type RulePayload struct {
+	Event string `json:"event"`
+	Rule  string `json:"rule"`
+	rules.Deploy
+	Seqno    uint32    `json:"seqno"`
+	TxHash   string    `json:"tx_hash,omitempty"`
+	Unixtime int64     `json:"unixtime"`
+	Links    LinksInfo `json:"links"`
+}
+
+// NotifyRule отправляет деплой в маршрут правила (tg_chat_id / webhook_url, иначе основной).
+func (n *Notifier) NotifyRule(ctx context.Context, hit *rules.Hit, event *ton.Event) {
+	n.consoleRule(hit)
+
+	chatID := hit.Rule.TgChatID
+	if chatID == "" {
+		chatID = n.tgChatID
+	}
+	if n.tgToken != "" && chatID != "" {
+		if err := n.sendTelegramTo(ctx, chatID, ruleText(hit)); err != nil {
+			n.logger.Warn("ошибка отправки в Telegram", zap.Error(err))
+		}
+	}
+
+	webhookURL := hit.Rule.WebhookURL
+	if webhookURL == "" {
+		webhookURL = n.webhookURL
+	}
+	if webhookURL != "" {
+		if err := n.postWebhookTo(ctx, webhookURL, ruleEventName, "", buildRulePayload(hit, event)); err != nil {
+			n.logger.Warn("ошибка отправки в webhook", zap.Error(err))
+		}
+	}
+}
+
+// consoleRule выводит совпадение с правилом в консоль.
+func (n *Notifier) consoleRule(hit *rules.Hit) {
+	cyan := color.New(color.FgHiCyan, color.Bold)
+	white := color.New(color.FgWhite)
+
+	fmt.Println()
+	cyan.Printf("  📐 ПРАВИЛО %s: %s %s\n", hit.Rule.Name, hit.Deploy.Kind, truncateHash(hit.Deploy.Address))
+	white.Printf("  Code hash: %s\n", truncateHash(hit.Deploy.CodeHash))
+	if len(hit.Deploy.Interfaces) > 0 {
+		white.Printf("  Методы:    %s\n", strings.Join(hit.Deploy.Interfaces, ", "))
+	}
+	fmt.Println()
+}
+
+// ruleText формирует текст для Telegram.
+func ruleText(hit *rules.Hit) string {
+	d := hit.Deploy
+	deployer := d.Deployer
+	if deployer == "" {
+		deployer = "—"
+	}
+
+	return fmt.Sprintf(
+		"📐 %s\n\n"+
+			"📦 Тип: %s\n"+
+			"📍 Адрес: %s\n"+
+			"🧬 Code hash: %s\n"+
+			"👤 Деплоер: %s\n\n"+
+			"🔍 Tonviewer: %s%s",
+		hit.Rule.Name,
+		d.Kind,
+		d.Address,
+		d.CodeHash,
+		deployer,
+		tonViewerBase, d.Address,
+	)
+}
+
+// buildRulePayload собирает JSON для webhook.
+func buildRulePayload(hit *rules.Hit, event *ton.Event) RulePayload {
+	payload := RulePayload{
+		Event:    ruleEventName,
+		Rule:     hit.Rule.Name,
+		Deploy:   hit.Deploy,
+		Unixtime: time.Now().Unix(),
+		Links: LinksInfo{
+			Tonviewer:   tonViewerBase + hit.Deploy.Address,
+			Tonscan:     tonscanBase + hit.Deploy.Address,
+			DexScreener: dexScreenerURL + hit.Deploy.Address,
+		},
+	}
+	if event != nil {
+		payload.Seqno = event.Seqno
+		payload.TxHash = event.TxHash
+		payload.Unixtime = event.Timestamp.Unix()
+	}
+	return payload
+}
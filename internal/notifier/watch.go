@@ -0,0 +1,158 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/yourname/hyper-sniper-indexer/internal/watchlist"
+	"github.com/yourname/hyper-sniper-indexer/pkg/ton"
+	"go.uber.org/zap"
+)
+
+const (
+	watchEventName = "watchlist_hit"
+	watchPriority  = "high"
+)
+
+// WatchPayload — JSON о событии из списка наблюдения.
+type WatchPayload struct {
+	Event        string       `json:"event"`
+	Priority     string       `json:"priority"`
+	Address      string       `json:"address"`
+	IsDeploy     bool         `json:"is_deploy"`
+	ContractKind string       `json:"contract_kind,omitempty"`
+	CodeHash     string       `json:"code_hash,omitempty"`
+	Sender       string       `json:"sender,omitempty"`
+	Matches      []WatchMatch `json:"matches"`
+	Workchain    int32        `json:"workchain"`
+	Seqno        uint32       `json:"seqno"`
+	TxHash       string       `json:"tx_hash,omitempty"`
+	TxLT         uint64       `json:"tx_lt,omitempty"`
+	Unixtime     int64        `json:"unixtime"`
+	Links        LinksInfo    `json:"links"`
+}
+
+type WatchMatch struct {
+	Kind  string `json:"kind"`
+	Value string `json:"value"`
+	Label string `json:"label,omitempty"`
+	Field string `json:"field"`
+}
+
+// NotifyWatch отправляет совпадение со списком наблюдения в отдельный маршрут
+// (watch_tg_chat_id / watch_webhook_url, иначе основной) с высоким приоритетом.
+func (n *Notifier) NotifyWatch(ctx context.Context, hit *watchlist.Hit, event *ton.Event) {
+	n.consoleWatch(hit)
+
+	chatID := n.watchChatID
+	if chatID == "" {
+		chatID = n.tgChatID
+	}
+	if n.tgToken != "" && chatID != "" {
+		if err := n.sendTelegramTo(ctx, chatID, watchText(hit)); err != nil {
+			n.logger.Warn("ошибка отправки в Telegram", zap.Error(err))
+		}
+	}
+
+	webhookURL := n.watchWebhookURL
+	if webhookURL == "" {
+		webhookURL = n.webhookURL
+	}
+	if webhookURL != "" {
+		if err := n.postWebhookTo(ctx, webhookURL, watchEventName, watchPriority, buildWatchPayload(hit, event)); err != nil {
+			n.logger.Warn("ошибка отправки в webhook", zap.Error(err))
+		}
+	}
+}
+
+// consoleWatch выводит событие из списка наблюдения в консоль.
+func (n *Notifier) consoleWatch(hit *watchlist.Hit) {
+	magenta := color.New(color.FgHiMagenta, color.Bold)
+	white := color.New(color.FgWhite)
+
+	what := "транзакция"
+	if hit.IsDeploy {
+		what = "деплой"
+	}
+
+	fmt.Println()
+	magenta.Printf("  👁 СПИСОК НАБЛЮДЕНИЯ: %s %s\n", what, truncateHash(hit.Address))
+	for _, m := range hit.Matches {
+		white.Printf("  %s: %s %s\n", m.Field, truncateHash(m.Value), m.Label)
+	}
+	fmt.Println()
+}
+
+// watchText формирует текст для Telegram.
+func watchText(hit *watchlist.Hit) string {
+	what := "Транзакция"
+	if hit.IsDeploy {
+		what = "Деплой"
+	}
+	if hit.ContractKind != "" {
+		what += " (" + hit.ContractKind + ")"
+	}
+
+	lines := make([]string, 0, len(hit.Matches))
+	for _, m := range hit.Matches {
+		line := fmt.Sprintf("• %s: %s", m.Field, m.Value)
+		if m.Label != "" {
+			line += " — " + m.Label
+		}
+		lines = append(lines, line)
+	}
+
+	return fmt.Sprintf(
+		"👁 СПИСОК НАБЛЮДЕНИЯ\n\n"+
+			"%s\n"+
+			"📍 Адрес: %s\n"+
+			"%s\n\n"+
+			"🔍 Tonviewer: %s%s",
+		what,
+		hit.Address,
+		strings.Join(lines, "\n"),
+		tonViewerBase, hit.Address,
+	)
+}
+
+// buildWatchPayload собирает JSON для webhook.
+func buildWatchPayload(hit *watchlist.Hit, event *ton.Event) WatchPayload {
+	payload := WatchPayload{
+		Event:        watchEventName,
+		Priority:     watchPriority,
+		Address:      hit.Address,
+		IsDeploy:     hit.IsDeploy,
+		ContractKind: hit.ContractKind,
+		Matches:      make([]WatchMatch, 0, len(hit.Matches)),
+		Unixtime:     time.Now().Unix(),
+		Links: LinksInfo{
+			Tonviewer:   tonViewerBase + hit.Address,
+			Tonscan:     tonscanBase + hit.Address,
+			DexScreener: dexScreenerURL + hit.Address,
+		},
+	}
+
+	for _, m := range hit.Matches {
+		payload.Matches = append(payload.Matches, WatchMatch{
+			Kind:  string(m.Kind),
+			Value: m.Value,
+			Label: m.Label,
+			Field: m.Field,
+		})
+	}
+
+	if event != nil {
+		payload.CodeHash = event.CodeHash
+		payload.Sender = event.Deployer
+		payload.Workchain = event.Workchain
+		payload.Seqno = event.Seqno
+		payload.TxHash = event.TxHash
+		payload.TxLT = event.TxLT
+		payload.Unixtime = event.Timestamp.Unix()
+	}
+
+	return payload
+}
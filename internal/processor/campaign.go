@@ -0,0 +1,85 @@
+package processor
+
+import (
+	"context"
+	"time"
+
+	"github.com/yourname/hyper-sniper-indexer/internal/detector"
+	"github.com/yourname/hyper-sniper-indexer/internal/trace"
+	"github.com/yourname/hyper-sniper-indexer/pkg/ton"
+	"go.uber.org/zap"
+)
+
+// Как часто проверяем, что кампании затихли
+const campaignPollInterval = 30 * time.Second
+
+// SetCampaigns включает обнаружение спам-кампаний и сворачивание их уведомлений в сводку.
+func (p *Processor) SetCampaigns(c *detector.CampaignDetector) {
+	p.campaigns = c
+}
+
+// suppressCampaign учитывает минтер в детекторе кампаний и возвращает true, если он часть
+// кампании: отдельное уведомление не отправляется, при обнаружении уходит campaign_started.
+// Деплоеры из allow-листа (лаунчпады) в кампании не попадают: их уведомления приоритетны.
+func (p *Processor) suppressCampaign(ctx context.Context, meta *detector.Metadata, event ton.Event) bool {
+	if p.campaigns == nil {
+		return false
+	}
+	if meta.Deployer != nil && meta.Deployer.List == detector.DeployerAllowed {
+		return false
+	}
+
+	// Окно кампании считается по времени блоков
+	at := event.Timestamp
+	if at.IsZero() {
+		at = time.Now()
+	}
+	camp, started := p.campaigns.Observe(meta, event.Deployer, at)
+	if camp == nil {
+		return false
+	}
+
+	p.logger.Info("уведомление подавлено: спам-кампания",
+		zap.String("address", meta.Address),
+		zap.String("by", camp.By),
+		zap.String("value", camp.Value),
+		zap.Int("count", camp.Count),
+	)
+	if rec := trace.FromContext(ctx); rec != nil {
+		rec.Decide(trace.VerdictSuppressed, string(detector.KindJettonMinter), "спам-кампания по "+camp.By)
+	}
+	if started && p.notifier != nil {
+		p.notifier.NotifyCampaign(ctx, camp)
+	}
+	return true
+}
+
+// RunCampaigns отправляет итоговые сводки затихших кампаний до отмены ctx.
+func (p *Processor) RunCampaigns(ctx context.Context) {
+	if p.campaigns == nil {
+		return
+	}
+
+	ticker := time.NewTicker(campaignPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		for _, camp := range p.campaigns.Expire(p.campaigns.Clock(time.Now())) {
+			p.logger.Info("спам-кампания завершена",
+				zap.String("by", camp.By),
+				zap.String("value", camp.Value),
+				zap.Int("count", camp.Count),
+				zap.Int("suppressed", camp.Suppressed),
+			)
+			if p.notifier != nil {
+				p.notifier.NotifyCampaign(ctx, camp)
+			}
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package processor
+
+import (
+	"context"
+
+	"github.com/yourname/hyper-sniper-indexer/internal/detector"
+	"github.com/yourname/hyper-sniper-indexer/pkg/ton"
+	"go.uber.org/zap"
+)
+
+// SetDexRouters заменяет список контрактов, деплоящих пулы (raw-адрес -> DEX).
+func (p *Processor) SetDexRouters(routers map[string]string) {
+	p.routers = routers
+}
+
+// handlePool привязывает пул, задеплоенный роутером или фабрикой DEX, к недавно найденному jetton
+// и отправляет pool_created. Пулы от других деплоеров остаются обычным контрактом.
+func (p *Processor) handlePool(ctx context.Context, cls *detector.Classification, event ton.Event) {
+	dex, ok := p.routers[event.Deployer]
+	if !ok || p.pools == nil || cls.Pool.DEX != dex {
+		return
+	}
+
+	tokens := [2]string{cls.Pool.Token0, cls.Pool.Token1}
+	var masters [2]string
+	for i, token := range tokens {
+		masters[i] = p.tokenMaster(ctx, dex, token)
+	}
+
+	idx := -1
+	for i, master := range masters {
+		if master != "" && master != detector.NativeTON && p.minters.has(master) {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		return
+	}
+
+	state := poolState{minter: masters[idx], jettonIndex: idx, quote: masters[1-idx], info: *cls.Pool}
+	p.minters.addPool(cls.Address, &state)
+	_, decimals, _ := p.minters.pool(cls.Address)
+
+	ev := poolEvent(detector.PoolCreated, cls.Address, state, decimals)
+	p.logger.Info("💧 создан пул для нового jetton",
+		zap.String("pool", ev.Pool),
+		zap.String("minter", ev.Minter),
+		zap.String("dex", ev.DEX),
+		zap.String("quote", ev.Quote),
+		zap.String("price", ev.Price),
+	)
+	if p.notifier != nil {
+		p.notifier.NotifyPool(ctx, ev)
+	}
+}
+
+// tokenMaster возвращает минтер токена пула. DeDust хранит минтеры, STON.fi — кошельки роутера:
+// минтер берём из реестра кошельков, иначе из get_wallet_data.
+func (p *Processor) tokenMaster(ctx context.Context, dex, token string) string {
+	if dex == detector.DexDeDust {
+		return token
+	}
+	if minter, ok := p.minters.minterOf(token); ok && minter != token {
+		return minter
+	}
+	return p.pools.JettonMaster(ctx, token)
+}
+
+// handleLiquidity отправляет liquidity_added для пула недавно найденного jetton.
+// Резервы DeDust приходят в событии deposit, резервы STON.fi читаются get_pool_data.
+func (p *Processor) handleLiquidity(ctx context.Context, event ton.Event) {
+	op := event.Dex
+	if op == nil || op.Aborted {
+		return
+	}
+	state, decimals, ok := p.minters.pool(event.AccountAddress)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, p.timeouts.Verify)
+	defer cancel()
+
+	if op.Reserve0 != "" {
+		state.info.Reserve0, state.info.Reserve1 = op.Reserve0, op.Reserve1
+	} else if p.pools != nil {
+		if fresh := p.pools.Inspect(ctx, state.info.DEX, event.AccountAddress); fresh != nil {
+			state.info.Reserve0, state.info.Reserve1 = fresh.Reserve0, fresh.Reserve1
+		}
+	}
+
+	ev := poolEvent(detector.LiquidityAdded, event.AccountAddress, state, decimals)
+	ev.Provider = op.Provider
+	ev.Amount0, ev.Amount1 = op.Amount0, op.Amount1
+	ev.First = p.minters.markLiquidity(event.AccountAddress)
+
+	p.logger.Info("💧 добавлена ликвидность",
+		zap.String("pool", ev.Pool),
+		zap.String("minter", ev.Minter),
+		zap.String("reserve0", ev.Reserve0),
+		zap.String("reserve1", ev.Reserve1),
+		zap.String("price", ev.Price),
+		zap.Bool("first", ev.First),
+	)
+	if p.notifier != nil {
+		p.notifier.NotifyPool(ctx, ev)
+	}
+}
+
+// poolEvent собирает событие пула; цена считается только для пары с TON.
+func poolEvent(kind, addr string, state poolState, decimals int) *detector.PoolEvent {
+	ev := &detector.PoolEvent{
+		Kind:        kind,
+		Pool:        addr,
+		Minter:      state.minter,
+		PoolInfo:    state.info,
+		JettonIndex: state.jettonIndex,
+		Quote:       state.quote,
+	}
+	if state.quote == detector.NativeTON {
+		reserves := [2]string{state.info.Reserve0, state.info.Reserve1}
+		ev.Price = detector.PoolPrice(reserves[state.jettonIndex], reserves[1-state.jettonIndex], decimals)
+	}
+	return ev
+}
+
+// SetLPLockers задаёт контракты-локеры LP (raw-адрес -> название).
+func (p *Processor) SetLPLockers(lockers map[string]string) {
+	p.lockers = lockers
+}
+
+// handleLPOp отправляет lp_burned / lp_locked, когда LP-токены пула переводятся
+// на нулевой адрес или на известный локер.
+func (p *Processor) handleLPOp(ctx context.Context, event ton.Event, pool, wallet string) {
+	op := event.Op
+	if op.Kind != ton.OpTransfer || op.Aborted {
+		return
+	}
+
+	var kind, locker string
+	if op.Destination == detector.BurnAddress {
+		kind = detector.LPBurned
+	} else if name, ok := p.lockers[op.Destination]; ok {
+		kind, locker = detector.LPLocked, name
+	} else {
+		return
+	}
+
+	state, _, ok := p.minters.pool(pool)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, p.timeouts.Verify)
+	defer cancel()
+
+	ev := &detector.PoolEvent{
+		Kind:        kind,
+		Pool:        pool,
+		Minter:      state.minter,
+		PoolInfo:    state.info,
+		JettonIndex: state.jettonIndex,
+		Quote:       state.quote,
+		LPOwner:     event.Deployer,
+		LPWallet:    wallet,
+		LPAmount:    op.Amount,
+		Locker:      locker,
+	}
+	if data, err := p.detector.ReadJettonData(ctx, pool); err == nil {
+		ev.LPSupply = data.TotalSupply
+		ev.LPPercent = detector.SharePercent(op.Amount, data.TotalSupply)
+	} else {
+		p.logger.Debug("не удалось прочитать supply LP", zap.String("pool", pool), zap.Error(err))
+	}
+
+	p.logger.Info("🔒 LP выведены из оборота",
+		zap.String("event", kind),
+		zap.String("pool", pool),
+		zap.String("minter", ev.Minter),
+		zap.String("amount", ev.LPAmount),
+		zap.Float64("percent", ev.LPPercent),
+		zap.String("locker", locker),
+	)
+	if p.notifier != nil {
+		p.notifier.NotifyPool(ctx, ev)
+	}
+}
@@ -0,0 +1,47 @@
+package processor
+
+import (
+	"context"
+
+	"github.com/yourname/hyper-sniper-indexer/internal/detector"
+	"github.com/yourname/hyper-sniper-indexer/internal/rules"
+	"github.com/yourname/hyper-sniper-indexer/internal/watchlist"
+	"github.com/yourname/hyper-sniper-indexer/pkg/ton"
+)
+
+// asyncNotifier ставит уведомления в очередь стадии dispatch вместо синхронной отправки.
+// Контекст вызывающего ограничивает только постановку в очередь: он заканчивается вместе с проверкой,
+// поэтому отправка получает контекст воркера dispatch с дедлайном Timeouts.Notify
+// и отменяется при остановке конвейера.
+type asyncNotifier struct {
+	next  Notifier
+	queue *queue[func(context.Context)]
+}
+
+func (n *asyncNotifier) NotifyWithEvent(ctx context.Context, meta *detector.Metadata, event *ton.Event) {
+	n.queue.push(ctx, func(sendCtx context.Context) { n.next.NotifyWithEvent(sendCtx, meta, event) })
+}
+
+func (n *asyncNotifier) NotifyContract(ctx context.Context, cls *detector.Classification, event *ton.Event) {
+	n.queue.push(ctx, func(sendCtx context.Context) { n.next.NotifyContract(sendCtx, cls, event) })
+}
+
+func (n *asyncNotifier) NotifyWatch(ctx context.Context, hit *watchlist.Hit, event *ton.Event) {
+	n.queue.push(ctx, func(sendCtx context.Context) { n.next.NotifyWatch(sendCtx, hit, event) })
+}
+
+func (n *asyncNotifier) NotifyJettonOp(ctx context.Context, ev *ton.JettonEvent) {
+	n.queue.push(ctx, func(sendCtx context.Context) { n.next.NotifyJettonOp(sendCtx, ev) })
+}
+
+func (n *asyncNotifier) NotifyPool(ctx context.Context, ev *detector.PoolEvent) {
+	n.queue.push(ctx, func(sendCtx context.Context) { n.next.NotifyPool(sendCtx, ev) })
+}
+
+func (n *asyncNotifier) NotifyRule(ctx context.Context, hit *rules.Hit, event *ton.Event) {
+	n.queue.push(ctx, func(sendCtx context.Context) { n.next.NotifyRule(sendCtx, hit, event) })
+}
+
+func (n *asyncNotifier) NotifyCampaign(ctx context.Context, camp *detector.Campaign) {
+	n.queue.push(ctx, func(sendCtx context.Context) { n.next.NotifyCampaign(sendCtx, camp) })
+}
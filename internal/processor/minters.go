@@ -0,0 +1,284 @@
+package processor
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/yourname/hyper-sniper-indexer/internal/detector"
+	"github.com/yourname/hyper-sniper-indexer/pkg/ton"
+	"go.uber.org/zap"
+)
+
+const (
+	// Сколько после обнаружения разбираем операции минтера и его кошельков из потока транзакций
+	minterOpWindow = time.Hour
+
+	// Лимит минтеров под разбором операций
+	minterOpMax = 20000
+
+	// Лимит кошельков, привязанных к минтерам
+	walletOpMax = 200000
+)
+
+// minterState — недавно найденный минтер, чьи операции ловим в потоке транзакций.
+type minterState struct {
+	detectedAt time.Time
+	decimals   int
+	minted     bool // первичный mint уже был (в сообщении деплоя или позже)
+}
+
+// poolState — пул DEX недавно найденного jetton.
+type poolState struct {
+	minter      string
+	jettonIndex int    // какой токен пула — наш jetton
+	quote       string // минтер второго токена или detector.NativeTON
+	info        detector.PoolInfo
+	liquidity   bool // ликвидность уже добавлялась
+}
+
+// recentMinters — недавно найденные минтеры, их кошельки и пулы: для них processShard
+// отдаёт транзакции без деплоя.
+type recentMinters struct {
+	window  time.Duration
+	mu      sync.RWMutex
+	items   map[string]*minterState
+	wallets map[string]string // кошелёк -> минтер
+	pools   map[string]*poolState
+}
+
+func newRecentMinters() *recentMinters {
+	return &recentMinters{
+		window:  minterOpWindow,
+		items:   make(map[string]*minterState),
+		wallets: make(map[string]string),
+		pools:   make(map[string]*poolState),
+	}
+}
+
+// add регистрирует минтер. minted — mint пришёл в сообщении деплоя.
+func (m *recentMinters) add(addr string, decimals int, minted bool, now time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.items) >= minterOpMax {
+		m.expire(now)
+	}
+	if len(m.items) >= minterOpMax {
+		return
+	}
+	m.items[addr] = &minterState{detectedAt: now, decimals: decimals, minted: minted}
+}
+
+// addPool привязывает пул к минтеру.
+func (m *recentMinters) addPool(addr string, pool *poolState) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.items[pool.minter]; ok {
+		m.pools[addr] = pool
+	}
+}
+
+// pool возвращает копию пула недавно найденного jetton и десятичные знаки jetton.
+func (m *recentMinters) pool(addr string) (poolState, int, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	p, ok := m.pools[addr]
+	if !ok || !m.active(p.minter) {
+		return poolState{}, 0, false
+	}
+	return *p, m.items[p.minter].decimals, true
+}
+
+// markLiquidity отмечает добавление ликвидности и возвращает true, если оно первое для пула.
+func (m *recentMinters) markLiquidity(addr string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p, ok := m.pools[addr]
+	if !ok || p.liquidity {
+		return false
+	}
+	p.liquidity = true
+	return true
+}
+
+// addWallet привязывает кошелёк к минтеру (для LP-кошелька минтер — пул).
+func (m *recentMinters) addWallet(wallet, minter string, now time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.tracked(minter) {
+		return
+	}
+	if len(m.wallets) >= walletOpMax {
+		m.expire(now)
+	}
+	if len(m.wallets) >= walletOpMax {
+		return
+	}
+	m.wallets[wallet] = minter
+}
+
+// has проверяет, что минтер найден недавно.
+func (m *recentMinters) has(addr string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.active(addr)
+}
+
+// minterOf возвращает минтер для адреса минтера или его кошелька. Пул считается
+// минтером своих LP-токенов.
+func (m *recentMinters) minterOf(addr string) (string, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.tracked(addr) {
+		return addr, true
+	}
+	if minter, ok := m.wallets[addr]; ok && m.tracked(minter) {
+		return minter, true
+	}
+	return "", false
+}
+
+// active проверяет окно минтера. Вызывается под m.mu.
+func (m *recentMinters) active(minter string) bool {
+	state, ok := m.items[minter]
+	return ok && time.Since(state.detectedAt) < m.window
+}
+
+// tracked проверяет минтер или пул недавно найденного jetton. Вызывается под m.mu.
+func (m *recentMinters) tracked(master string) bool {
+	if m.active(master) {
+		return true
+	}
+	pool, ok := m.pools[master]
+	return ok && m.active(pool.minter)
+}
+
+// markMinted отмечает mint и возвращает true, если он первый для минтера.
+func (m *recentMinters) markMinted(addr string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	state, ok := m.items[addr]
+	if !ok || state.minted {
+		return false
+	}
+	state.minted = true
+	return true
+}
+
+// expire удаляет минтеры старше окна вместе с их кошельками и пулами. Вызывается под m.mu.
+func (m *recentMinters) expire(now time.Time) {
+	for addr, state := range m.items {
+		if now.Sub(state.detectedAt) >= m.window {
+			delete(m.items, addr)
+		}
+	}
+	for wallet, minter := range m.wallets {
+		if _, ok := m.items[minter]; !ok {
+			delete(m.wallets, wallet)
+		}
+	}
+	for addr, pool := range m.pools {
+		if _, ok := m.items[pool.minter]; !ok {
+			delete(m.pools, addr)
+		}
+	}
+}
+
+// SetJettonWindow задаёт, сколько после обнаружения разбирать операции jetton
+// (не меньше часа по умолчанию). Вызывается до запуска обработки.
+func (p *Processor) SetJettonWindow(d time.Duration) {
+	if d > minterOpWindow {
+		p.minters.window = d
+	}
+}
+
+// WatchesJetton сообщает, разбираются ли транзакции адреса как операции
+// недавно найденного jetton — минтера, его кошелька, пула или LP-кошелька (ton.TxFilter).
+func (p *Processor) WatchesJetton(addr string) bool {
+	_, ok := p.minters.minterOf(addr)
+	return ok
+}
+
+// handleJettonOp отправляет операцию недавно найденного jetton: операции минтера
+// (mint, смена админа/content, burn_notification) и кошельков (переводы, burn).
+// Кошелёк привязывается к минтеру по первому internal_transfer от минтера или от уже известного кошелька.
+// Операции LP-кошельков пулов уходят в handleLPOp.
+func (p *Processor) handleJettonOp(ctx context.Context, event ton.Event) {
+	op := event.Op
+	if op == nil {
+		return
+	}
+
+	var minter, wallet string
+	switch op.Kind {
+	case ton.OpInternalTransfer:
+		// Входящий перевод на кошелёк: отправитель — минтер (mint) или другой кошелёк
+		m, ok := p.minters.minterOf(event.Deployer)
+		if !ok {
+			return
+		}
+		minter, wallet = m, event.AccountAddress
+		if !op.Aborted {
+			p.minters.addWallet(wallet, minter, time.Now())
+		}
+	case ton.OpTransferNotification:
+		// Уведомление владельцу приходит от его кошелька
+		m, ok := p.minters.minterOf(event.Deployer)
+		if !ok || m == event.Deployer {
+			return
+		}
+		minter, wallet = m, event.Deployer
+	case ton.OpTransfer, ton.OpBurn:
+		m, ok := p.minters.minterOf(event.AccountAddress)
+		if !ok || m == event.AccountAddress {
+			return
+		}
+		minter, wallet = m, event.AccountAddress
+	default:
+		if !p.minters.has(event.AccountAddress) {
+			return
+		}
+		minter = event.AccountAddress
+	}
+
+	if _, _, ok := p.minters.pool(minter); ok {
+		p.handleLPOp(ctx, event, minter, wallet)
+		return
+	}
+
+	initial := op.Kind == ton.OpMint && !op.Aborted && p.minters.markMinted(minter)
+	ev := &ton.JettonEvent{
+		Minter:  minter,
+		Wallet:  wallet,
+		Initial: initial,
+		Event:   event,
+	}
+	if p.holders != nil {
+		p.holders.Apply(ev)
+	}
+
+	p.logger.Info("операция jetton",
+		zap.String("minter", minter),
+		zap.String("wallet", wallet),
+		zap.String("op", string(op.Kind)),
+		zap.String("destination", op.Destination),
+		zap.String("amount", op.Amount),
+		zap.Bool("initial", initial),
+		zap.Bool("aborted", op.Aborted),
+		zap.String("tx_hash", event.TxHash),
+	)
+
+	if p.notifier != nil {
+		ctx, cancel := context.WithTimeout(ctx, p.timeouts.Notify)
+		defer cancel()
+		p.notifier.NotifyJettonOp(ctx, ev)
+	}
+}
@@ -0,0 +1,415 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/yourname/hyper-sniper-indexer/pkg/ton"
+	"go.uber.org/zap"
+)
+
+// Политика переполнения очереди.
+const (
+	OverflowBlock      = "block"       // ждать место: обратное давление на processShard
+	OverflowDropNewest = "drop_newest" // отбросить новое событие
+	OverflowDropOldest = "drop_oldest" // вытеснить самое старое событие из очереди
+)
+
+const (
+	defaultQueueSize       = 1000
+	defaultWorkers         = 8
+	defaultDispatchWorkers = 4
+
+	// Как часто пишем глубину очередей в лог и как часто предупреждаем о потерях
+	pipelineStatsInterval = time.Minute
+	pipelineDropLogEvery  = 10 * time.Second
+)
+
+// PipelineConfig задаёт асинхронный конвейер обработки. Нулевые значения заменяются значениями по умолчанию.
+type PipelineConfig struct {
+	QueueSize         int // размер очереди каждой стадии; у dedupe делится между шардами воркеров
+	Workers           int // воркеры dedupe (и по умолчанию verify и enrich)
+	VerifyWorkers     int
+	EnrichWorkers     int
+	DispatchQueueSize int
+	DispatchWorkers   int
+	Overflow          string // Overflow*
+}
+
+// QueueStats — метрики очереди стадии конвейера.
+type QueueStats struct {
+	Stage     string `json:"stage"`
+	Depth     int    `json:"depth"`
+	Capacity  int    `json:"capacity"`
+	MaxDepth  int64  `json:"max_depth"` // максимальная глубина с запуска
+	Workers   int    `json:"workers"`
+	Enqueued  int64  `json:"enqueued"`
+	Dropped   int64  `json:"dropped"`
+	Processed int64  `json:"processed"`
+	Overflow  string `json:"overflow"`
+}
+
+// queue — ограниченная очередь стадии с политикой переполнения и счётчиками.
+// С ключом очередь шардирована: у каждого воркера свой канал, элемент попадает в канал по хэшу ключа,
+// поэтому элементы с одним ключом обрабатываются по порядку одним воркером.
+// Без ключа все воркеры читают общий канал.
+type queue[T any] struct {
+	stage    string
+	shards   []chan T
+	key      func(T) string
+	discard  func(T) // вызывается для отброшенного элемента (nil — ничего не делать)
+	workers  int
+	overflow string
+	done     chan struct{}
+	logger   *zap.Logger
+
+	enqueued  atomic.Int64
+	dropped   atomic.Int64
+	processed atomic.Int64
+	maxDepth  atomic.Int64
+	dropLog   atomic.Int64 // unixnano последнего предупреждения о потерях
+}
+
+func newQueue[T any](stage string, size, workers int, key func(T) string, overflow string, done chan struct{}, logger *zap.Logger) *queue[T] {
+	shards := 1
+	if key != nil {
+		shards = workers
+	}
+	q := &queue[T]{
+		stage:    stage,
+		shards:   make([]chan T, shards),
+		key:      key,
+		workers:  workers,
+		overflow: overflow,
+		done:     done,
+		logger:   logger,
+	}
+	for i := range q.shards {
+		q.shards[i] = make(chan T, max(size/shards, 1))
+	}
+	return q
+}
+
+// shard выбирает канал элемента.
+func (q *queue[T]) shard(item T) chan T {
+	if len(q.shards) == 1 {
+		return q.shards[0]
+	}
+	h := fnv.New32a()
+	h.Write([]byte(q.key(item)))
+	return q.shards[h.Sum32()%uint32(len(q.shards))]
+}
+
+func (q *queue[T]) depth() int {
+	depth := 0
+	for _, ch := range q.shards {
+		depth += len(ch)
+	}
+	return depth
+}
+
+func (q *queue[T]) capacity() int {
+	return len(q.shards) * cap(q.shards[0])
+}
+
+// push ставит элемент в очередь по политике переполнения. false — элемент отброшен.
+// При политике block ожидание прерывается отменой ctx или остановкой конвейера.
+// После остановки конвейера элементы не принимаются: их некому обработать.
+func (q *queue[T]) push(ctx context.Context, item T) bool {
+	select {
+	case <-q.done:
+		q.discardItem(item)
+		return false
+	default:
+	}
+
+	ch := q.shard(item)
+	select {
+	case ch <- item:
+		q.accepted()
+		return true
+	default:
+	}
+
+	switch q.overflow {
+	case OverflowBlock:
+		select {
+		case ch <- item:
+			q.accepted()
+			return true
+		case <-q.done:
+			q.drop(item)
+			return false
+		case <-ctx.Done():
+			q.drop(item)
+			return false
+		}
+	case OverflowDropOldest:
+		for {
+			select {
+			case ch <- item:
+				q.accepted()
+				return true
+			default:
+			}
+			select {
+			case old := <-ch:
+				q.drop(old)
+			default:
+			}
+		}
+	}
+	q.drop(item)
+	return false
+}
+
+func (q *queue[T]) accepted() {
+	q.enqueued.Add(1)
+	depth := int64(q.depth())
+	for {
+		cur := q.maxDepth.Load()
+		if depth <= cur || q.maxDepth.CompareAndSwap(cur, depth) {
+			return
+		}
+	}
+}
+
+// discardItem освобождает отброшенный элемент и учитывает его.
+func (q *queue[T]) discardItem(item T) int64 {
+	if q.discard != nil {
+		q.discard(item)
+	}
+	return q.dropped.Add(1)
+}
+
+// drop учитывает потерянный элемент и не чаще pipelineDropLogEvery пишет предупреждение.
+func (q *queue[T]) drop(item T) {
+	dropped := q.discardItem(item)
+	now := time.Now().UnixNano()
+	last := q.dropLog.Load()
+	if now-last < int64(pipelineDropLogEvery) || !q.dropLog.CompareAndSwap(last, now) {
+		return
+	}
+	q.logger.Warn("очередь конвейера переполнена, события теряются",
+		zap.String("stage", q.stage),
+		zap.Int("capacity", q.capacity()),
+		zap.Int64("dropped_total", dropped),
+		zap.String("overflow", q.overflow),
+	)
+}
+
+// run обслуживает очередь workers горутинами до отмены ctx.
+func (q *queue[T]) run(ctx context.Context, wg *sync.WaitGroup, handle func(T)) {
+	for i := 0; i < q.workers; i++ {
+		ch := q.shards[i%len(q.shards)]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case item := <-ch:
+					handle(item)
+					q.processed.Add(1)
+				}
+			}
+		}()
+	}
+}
+
+// drain забирает элементы, оставшиеся в каналах после остановки воркеров,
+// и учитывает их как отброшенные. Возвращает их число.
+func (q *queue[T]) drain() int64 {
+	var n int64
+	for _, ch := range q.shards {
+		for len(ch) > 0 {
+			q.discardItem(<-ch)
+			n++
+		}
+	}
+	return n
+}
+
+func (q *queue[T]) stats() QueueStats {
+	return QueueStats{
+		Stage:     q.stage,
+		Depth:     q.depth(),
+		Capacity:  q.capacity(),
+		MaxDepth:  q.maxDepth.Load(),
+		Workers:   q.workers,
+		Enqueued:  q.enqueued.Load(),
+		Dropped:   q.dropped.Load(),
+		Processed: q.processed.Load(),
+		Overflow:  q.overflow,
+	}
+}
+
+// pipeline — асинхронный конвейер Processor. Каждая стадия — своя очередь и свой пул воркеров:
+// dedupe разбирает события и занимает адрес деплоя в кэше, verify ждёт аккаунт и прогоняет детекторы,
+// enrich считает риск и репутацию, dispatch доставляет уведомления. Медленная стадия
+// видна по глубине своей очереди и не задерживает разбор блоков.
+type pipeline struct {
+	events   *queue[ton.Event]
+	verify   *queue[*deployJob]
+	enrich   *queue[*deployJob]
+	dispatch *queue[func(context.Context)]
+	done     chan struct{}
+	logger   *zap.Logger
+}
+
+// SetPipeline включает асинхронный конвейер: Handle только ставит событие в очередь.
+// Вызывается после настройки уведомлений и до запуска обработки; воркеры запускает RunPipeline.
+func (p *Processor) SetPipeline(cfg PipelineConfig) error {
+	switch cfg.Overflow {
+	case "":
+		cfg.Overflow = OverflowBlock
+	case OverflowBlock, OverflowDropNewest, OverflowDropOldest:
+	default:
+		return fmt.Errorf("неизвестная политика переполнения: %q", cfg.Overflow)
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = defaultQueueSize
+	}
+	if cfg.Workers <= 0 {
+		cfg.Workers = defaultWorkers
+	}
+	if cfg.VerifyWorkers <= 0 {
+		cfg.VerifyWorkers = cfg.Workers
+	}
+	if cfg.EnrichWorkers <= 0 {
+		cfg.EnrichWorkers = cfg.Workers
+	}
+	if cfg.DispatchQueueSize <= 0 {
+		cfg.DispatchQueueSize = cfg.QueueSize
+	}
+	if cfg.DispatchWorkers <= 0 {
+		cfg.DispatchWorkers = defaultDispatchWorkers
+	}
+
+	done := make(chan struct{})
+	pl := &pipeline{
+		events:   newQueue("dedupe", cfg.QueueSize, cfg.Workers, p.shardKey, cfg.Overflow, done, p.logger),
+		verify:   newQueue[*deployJob]("verify", cfg.QueueSize, cfg.VerifyWorkers, nil, cfg.Overflow, done, p.logger),
+		enrich:   newQueue[*deployJob]("enrich", cfg.QueueSize, cfg.EnrichWorkers, nil, cfg.Overflow, done, p.logger),
+		dispatch: newQueue[func(context.Context)]("dispatch", cfg.DispatchQueueSize, cfg.DispatchWorkers, nil, cfg.Overflow, done, p.logger),
+		done:     done,
+		logger:   p.logger,
+	}
+	pl.verify.discard = p.abandon
+	pl.enrich.discard = p.abandon
+	p.pipeline = pl
+	if p.notifier != nil {
+		p.notifier = &asyncNotifier{next: p.notifier, queue: pl.dispatch}
+	}
+	return nil
+}
+
+// RunPipeline запускает воркеры стадий и до отмены ctx периодически пишет глубину очередей.
+// После отмены дожидается воркеров и отбрасывает с учётом оставшиеся в очередях элементы.
+func (p *Processor) RunPipeline(ctx context.Context) {
+	pl := p.pipeline
+	if pl == nil {
+		return
+	}
+
+	var wg sync.WaitGroup
+	pl.events.run(ctx, &wg, func(event ton.Event) {
+		if err := p.handle(ctx, event); err != nil {
+			p.logger.Warn("ошибка обработчика события", zap.Error(err))
+		}
+	})
+	pl.verify.run(ctx, &wg, func(job *deployJob) {
+		p.verify(ctx, job)
+	})
+	pl.enrich.run(ctx, &wg, func(job *deployJob) {
+		p.enrich(ctx, job)
+	})
+	pl.dispatch.run(ctx, &wg, func(send func(context.Context)) {
+		sendCtx, cancel := context.WithTimeout(ctx, p.timeouts.Notify)
+		defer cancel()
+		send(sendCtx)
+	})
+
+	ticker := time.NewTicker(pipelineStatsInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			close(pl.done)
+			wg.Wait()
+			pl.drain()
+			return
+		case <-ticker.C:
+			for _, s := range pl.stats() {
+				p.logger.Info("очередь конвейера",
+					zap.String("stage", s.Stage),
+					zap.Int("depth", s.Depth),
+					zap.Int("capacity", s.Capacity),
+					zap.Int64("max_depth", s.MaxDepth),
+					zap.Int64("processed", s.Processed),
+					zap.Int64("dropped", s.Dropped),
+				)
+			}
+		}
+	}
+}
+
+// shardKey — ключ шарда стадии dedupe: операции jetton идут по минтеру,
+// чтобы операции одного токена (и его кошельков) применялись по порядку, остальное — по адресу аккаунта.
+func (p *Processor) shardKey(event ton.Event) string {
+	if event.Op != nil {
+		// Как в handleJettonOp: входящий перевод и уведомление приходят от кошелька или минтера
+		from := event.AccountAddress
+		if event.Op.Kind == ton.OpInternalTransfer || event.Op.Kind == ton.OpTransferNotification {
+			from = event.Deployer
+		}
+		if minter, ok := p.minters.minterOf(from); ok {
+			return minter
+		}
+	}
+	return event.AccountAddress
+}
+
+// PipelineStats возвращает метрики очередей конвейера (nil — конвейер выключен).
+func (p *Processor) PipelineStats() []QueueStats {
+	if p.pipeline == nil {
+		return nil
+	}
+	return p.pipeline.stats()
+}
+
+// stopped сообщает, что конвейер остановлен.
+func (pl *pipeline) stopped() bool {
+	select {
+	case <-pl.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// drain после остановки воркеров отбрасывает необработанные элементы всех стадий по порядку конвейера:
+// занятые деплои освобождаются и будут найдены заново при catchup после перезапуска.
+func (pl *pipeline) drain() {
+	for _, q := range []interface {
+		drain() int64
+		stats() QueueStats
+	}{pl.events, pl.verify, pl.enrich, pl.dispatch} {
+		if n := q.drain(); n > 0 {
+			pl.logger.Warn("конвейер остановлен, необработанные элементы отброшены",
+				zap.String("stage", q.stats().Stage),
+				zap.Int64("dropped", n),
+			)
+		}
+	}
+}
+
+func (pl *pipeline) stats() []QueueStats {
+	return []QueueStats{pl.events.stats(), pl.verify.stats(), pl.enrich.stats(), pl.dispatch.stats()}
+}
@@ -2,98 +2,590 @@ package processor
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
 	"time"
 
+	"github.com/yourname/hyper-sniper-indexer/internal/archive"
 	"github.com/yourname/hyper-sniper-indexer/internal/detector"
-	"github.com/yourname/hyper-sniper-indexer/internal/notifier"
+	"github.com/yourname/hyper-sniper-indexer/internal/rules"
+	"github.com/yourname/hyper-sniper-indexer/internal/trace"
+	"github.com/yourname/hyper-sniper-indexer/internal/watchlist"
 	"github.com/yourname/hyper-sniper-indexer/pkg/ton"
 	"go.uber.org/zap"
 )
 
+const (
+	// Сколько следующих блоков мастерчейна пробуем, если аккаунт не виден на блоке деплоя
+	accountWaitBlocks = 3
+
+	// Общий лимит ожидания появления аккаунта
+	accountWaitTimeout = 8 * time.Second
+
+	// Дедлайн проверки деплоя (кэш, code_hash, детекторы, обогащение) и отправки уведомления
+	verifyTimeout = 5 * time.Second
+	notifyTimeout = 5 * time.Second
+
+	// Аренда адреса на время проверки не короче минуты; после неё отклонённый деплой снова проверяется
+	minClaimLease = time.Minute
+
+	// Дедлайн операций с арендой адреса в кэше
+	claimTimeout = 2 * time.Second
+)
+
+// Паузы между попытками: блок мастерчейна выходит примерно раз в 2-5 секунд
+var accountWaitBackoff = []time.Duration{0, 500 * time.Millisecond, 1500 * time.Millisecond, 3 * time.Second}
+
+// Timeouts — дедлайны стадий обработки. Нулевые значения заменяются значениями по умолчанию.
+type Timeouts struct {
+	Verify      time.Duration // проверка деплоя: кэш, code_hash, детекторы, обогащение
+	AccountWait time.Duration // ожидание появления аккаунта на liteserver'е
+	Notify      time.Duration // отправка одного уведомления
+}
+
 // Processor отвечает за обработку событий из ton-indexer.
+// Деплой прогоняется через цепочку ContractDetector: первый совпавший определяет тип контракта.
 type Processor struct {
-	detector *detector.Detector
-	client   ton.Client
-	cache    Cache
-	notifier *notifier.Notifier
-	logger   *zap.Logger
+	detector  *detector.Detector
+	detectors []detector.ContractDetector
+	prints    *detector.Fingerprinter
+	risk      *detector.RiskAnalyzer
+	imposters *detector.ImpersonationChecker
+	deployers *detector.DeployerReputation
+	campaigns *detector.CampaignDetector
+	rechecks  RecheckQueue
+	tracker   Tracker
+	archive   CodeArchive
+	decisions DecisionStore
+	watchlist Watchlist
+	minters   *recentMinters
+	pools     *detector.PoolDetector
+	routers   map[string]string // контракты, деплоящие пулы: адрес -> DEX
+	lockers   map[string]string // локеры LP: адрес -> название
+	holders   HolderBook
+	rules     RuleSet
+	client    ton.Client
+	cache     Cache
+	notifier  Notifier
+	pipeline  *pipeline
+	timeouts  Timeouts
+	logger    *zap.Logger
 
 	// Статистика
 	totalProcessed int64
 	totalDetected  int64
+	totalNotReady  int64
 }
 
-// Cache описывает минимальный интерфейс антидублирования.
+// Cache описывает минимальный интерфейс антидублирования (реализуется storage.RedisCache и storage.MemoryCache).
+// ClaimMinter атомарно занимает адрес на время проверки, поэтому один деплой проверяет и отправляет
+// только один воркер и один инстанс — и в realtime, и в catchup.
 type Cache interface {
 	RegisterSeqno(ctx context.Context, seqno uint32) (bool, error)
-	IsMinterKnown(ctx context.Context, address string) (bool, error)
-	RememberMinter(ctx context.Context, address string) error
+	ClaimMinter(ctx context.Context, address string, lease time.Duration) (bool, error)
+	ConfirmMinter(ctx context.Context, address string) error
+	ReleaseMinter(ctx context.Context, address string) error
+}
+
+// Notifier описывает доставку найденных контрактов (реализуется notifier.Notifier).
+type Notifier interface {
+	NotifyWithEvent(ctx context.Context, meta *detector.Metadata, event *ton.Event)
+	NotifyContract(ctx context.Context, cls *detector.Classification, event *ton.Event)
+	NotifyWatch(ctx context.Context, hit *watchlist.Hit, event *ton.Event)
+	NotifyJettonOp(ctx context.Context, ev *ton.JettonEvent)
+	NotifyPool(ctx context.Context, ev *detector.PoolEvent)
+	NotifyRule(ctx context.Context, hit *rules.Hit, event *ton.Event)
+	NotifyCampaign(ctx context.Context, camp *detector.Campaign)
+}
+
+// Watchlist сопоставляет события со списком наблюдения (реализуется watchlist.Watchlist).
+type Watchlist interface {
+	MatchEvent(event ton.Event) []watchlist.Match
+	MatchAddress(field, addr string) []watchlist.Match
+}
+
+// Tracker ставит найденные минтеры на периодическую перепроверку (реализуется tracker.Tracker).
+type Tracker interface {
+	Track(meta *detector.Metadata)
+}
+
+// HolderBook ведёт таблицу держателей недавно найденных jetton (реализуется holders.Tracker).
+type HolderBook interface {
+	Track(meta *detector.Metadata, deployer string)
+	Apply(ev *ton.JettonEvent)
+}
+
+// RuleSet сопоставляет деплои с правилами из конфига (реализуется rules.Set).
+type RuleSet interface {
+	Match(d rules.Deploy) []*rules.Rule
+	NeedsInterfaces() bool
+}
+
+// CodeArchive сохраняет BOC кода и данных контрактов с неизвестным code_hash (реализуется archive.Archive).
+type CodeArchive interface {
+	Store(entry archive.Entry, code, data []byte) (bool, error)
+}
+
+// DecisionStore хранит трассировку решений по деплоям (реализуется trace.Store).
+type DecisionStore interface {
+	Add(d *trace.Decision)
 }
 
 // NewProcessor создаёт обработчик.
-func NewProcessor(det *detector.Detector, client ton.Client, cache Cache, ntf *notifier.Notifier, logger *zap.Logger) *Processor {
+// Цепочка по умолчанию: Jetton Minter -> NFT-коллекция -> пул DEX.
+func NewProcessor(det *detector.Detector, client ton.Client, cache Cache, ntf Notifier, logger *zap.Logger) *Processor {
+	var risk *detector.RiskAnalyzer
+	var prints *detector.Fingerprinter
+	var pools *detector.PoolDetector
+	detectors := []detector.ContractDetector{det}
+	if client != nil {
+		risk = detector.NewRiskAnalyzer(client, logger)
+		prints = detector.NewFingerprinter(client, nil, 0, logger)
+		pools = detector.NewPoolDetector(client, logger)
+		detectors = append(detectors,
+			detector.NewNFTDetector(client, logger),
+			pools,
+		)
+	}
+
 	return &Processor{
-		detector: det,
-		client:   client,
-		cache:    cache,
-		notifier: ntf,
-		logger:   logger,
+		detector:  det,
+		detectors: detectors,
+		prints:    prints,
+		risk:      risk,
+		imposters: detector.NewImpersonationChecker(detector.DefaultProtectedTokens(), logger),
+		minters:   newRecentMinters(),
+		pools:     pools,
+		routers:   detector.DefaultDexRouters(),
+		client:    client,
+		cache:     cache,
+		notifier:  ntf,
+		timeouts:  Timeouts{Verify: verifyTimeout, AccountWait: accountWaitTimeout, Notify: notifyTimeout},
+		logger:    logger,
+	}
+}
+
+// SetTimeouts задаёт дедлайны стадий (из конфига); нулевые поля остаются по умолчанию.
+func (p *Processor) SetTimeouts(t Timeouts) {
+	if t.Verify > 0 {
+		p.timeouts.Verify = t.Verify
+	}
+	if t.AccountWait > 0 {
+		p.timeouts.AccountWait = t.AccountWait
+	}
+	if t.Notify > 0 {
+		p.timeouts.Notify = t.Notify
+	}
+}
+
+// SetFingerprinter заменяет модуль fingerprinting (набор методов и дедлайн из конфига).
+func (p *Processor) SetFingerprinter(f *detector.Fingerprinter) {
+	p.prints = f
+}
+
+// SetImpersonationChecker заменяет список защищённых токенов (из конфига).
+func (p *Processor) SetImpersonationChecker(c *detector.ImpersonationChecker) {
+	p.imposters = c
+}
+
+// SetDeployerReputation включает историю деплоеров и списки allow/block.
+func (p *Processor) SetDeployerReputation(r *detector.DeployerReputation) {
+	p.deployers = r
+}
+
+// SetArchive включает архивирование кода неизвестных контрактов.
+func (p *Processor) SetArchive(a CodeArchive) {
+	p.archive = a
+}
+
+// SetDecisionStore включает трассировку решений: почему деплой принят или отклонён.
+func (p *Processor) SetDecisionStore(s DecisionStore) {
+	p.decisions = s
+}
+
+// SetWatchlist включает уведомления по наблюдаемым адресам и code_hash.
+func (p *Processor) SetWatchlist(w Watchlist) {
+	p.watchlist = w
+}
+
+// SetTracker включает наблюдение за изменениями найденных минтеров.
+func (p *Processor) SetTracker(t Tracker) {
+	p.tracker = t
+}
+
+// SetHolders включает таблицу держателей по переводам из потока блоков.
+func (p *Processor) SetHolders(h HolderBook) {
+	p.holders = h
+}
+
+// SetRules включает правила из конфига: уведомления о деплоях любого класса контрактов.
+func (p *Processor) SetRules(r RuleSet) {
+	p.rules = r
+}
+
+// AddDetector добавляет детектор в конец цепочки.
+func (p *Processor) AddDetector(cd detector.ContractDetector) {
+	p.detectors = append(p.detectors, cd)
+}
+
+// Handle обрабатывает единичное событие из ton-indexer. С конвейером (SetPipeline)
+// событие только ставится в очередь по её политике переполнения; при политике block
+// ожидание места прерывается отменой ctx.
+func (p *Processor) Handle(ctx context.Context, event ton.Event) error {
+	if p.pipeline != nil {
+		p.pipeline.events.push(ctx, event)
+		return nil
 	}
+	return p.handle(ctx, event)
 }
 
-// Handle обрабатывает единичное событие из ton-indexer.
-func (p *Processor) Handle(event ton.Event) error {
-	// Пропускаем если это не деплой
+// handle синхронно обрабатывает событие. Отмена ctx прерывает проверки и отправку уведомлений.
+func (p *Processor) handle(ctx context.Context, event ton.Event) error {
+	// Список наблюдения срабатывает на любой деплой и на транзакции наблюдаемых адресов
+	if p.watchlist != nil {
+		if matches := p.watchlist.MatchEvent(event); len(matches) > 0 {
+			watchCtx, cancel := context.WithTimeout(ctx, p.timeouts.Notify)
+			p.watch(watchCtx, event, "", matches)
+			cancel()
+		}
+	}
+
+	// Операции недавно найденных jetton: деплой кошелька тоже приходит с internal_transfer
+	if event.Op != nil {
+		p.handleJettonOp(ctx, event)
+	}
+	if event.Dex != nil {
+		p.handleLiquidity(ctx, event)
+	}
+
+	// Дальше идут только деплои
 	if !event.IsDeploy {
 		return nil
 	}
 
-	p.totalProcessed++
+	atomic.AddInt64(&p.totalProcessed, 1)
+	p.process(ctx, event, 0)
+	return nil
+}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
+// deployJob — деплой на стадиях dedupe → verify → enrich.
+// В конвейере стадии передают его друг другу через очереди, без конвейера стадии идут подряд.
+type deployJob struct {
+	event   ton.Event
+	attempt int
+	rec     *trace.Recorder
+	claimed bool // адрес занят этим процессором
+	settled bool // решение принято: аренду не освобождаем
 
-	// Проверяем дубликаты по адресу (быстрее чем seqno)
-	if p.cache != nil {
-		seen, err := p.cache.IsMinterKnown(ctx, event.AccountAddress)
-		if err != nil {
-			p.logger.Warn("ошибка проверки минтера в кэше", zap.Error(err))
-		}
-		if seen {
-			return nil
-		}
+	ref ton.BlockRef // блок, на котором виден аккаунт
+	cls *detector.Classification
+}
+
+// process проверяет деплой. attempt — номер повторной проверки (0 — первичная обработка):
+// первичная идёт на блоке деплоя, повторные — на последнем блоке.
+func (p *Processor) process(ctx context.Context, event ton.Event, attempt int) {
+	job := &deployJob{
+		event:   event,
+		attempt: attempt,
+		rec:     trace.Begin(event.AccountAddress, event.Seqno, event.TxHash, attempt),
+	}
+	if !p.dedupe(ctx, job) {
+		return
 	}
+	if p.pipeline != nil {
+		p.pipeline.verify.push(ctx, job)
+		return
+	}
+	p.verify(ctx, job)
+}
 
-	// Получаем code_hash (если не пришёл в событии)
-	codeHash := event.CodeHash
-	if codeHash == "" {
-		ch, err := p.client.GetCodeHash(ctx, event.AccountAddress)
-		if err != nil {
+// dedupe занимает адрес: уже обработанный или проверяемый другим воркером/инстансом пропускаем.
+// Принятый адрес закрепляется в remember, отклонённый держит аренду до истечения,
+// отложенный или упавший освобождается для повторной проверки.
+func (p *Processor) dedupe(ctx context.Context, job *deployJob) bool {
+	if p.cache == nil {
+		return true
+	}
+
+	started := time.Now()
+	claimCtx, cancel := context.WithTimeout(ctx, claimTimeout)
+	claimed, err := p.cache.ClaimMinter(claimCtx, job.event.AccountAddress, p.claimLease())
+	cancel()
+	if err != nil {
+		p.logger.Warn("ошибка проверки минтера в кэше", zap.Error(err))
+	}
+	seen := err == nil && !claimed
+	job.rec.Step("cache", started, hitString(seen), err)
+	if seen {
+		job.rec.Decide(trace.VerdictDuplicate, "", "адрес уже обработан")
+		p.finish(ctx, job)
+		return false
+	}
+	job.claimed = claimed
+	return true
+}
+
+// verify находит аккаунт и прогоняет цепочку детекторов.
+// Ожидание аккаунта и проверка получают собственные дедлайны от ctx вызывающего;
+// дедлайн проверки отсчитывается после ожидания аккаунта и не расходуется на него.
+func (p *Processor) verify(parent context.Context, job *deployJob) {
+	event, attempt, rec := job.event, job.attempt, job.rec
+	parent = trace.WithRecorder(parent, rec)
+
+	// Проверяем контракт на блоке деплоя (или первом блоке после него, где виден аккаунт)
+	var codeHash string
+	var ref ton.BlockRef
+	var err error
+	started := time.Now()
+	if attempt == 0 {
+		codeHash, ref, err = p.waitForAccount(parent, event)
+	}
+
+	ctx, cancel := context.WithTimeout(parent, p.timeouts.Verify)
+	defer cancel()
+
+	if attempt > 0 {
+		codeHash, err = p.client.GetCodeHash(ctx, event.AccountAddress)
+	}
+	if err != nil {
+		rec.Step("code_hash", started, "failed", err)
+		if errors.Is(err, ton.ErrNotReady) {
+			if attempt == 0 {
+				atomic.AddInt64(&p.totalNotReady, 1)
+			}
+			p.logger.Warn("аккаунт не появился на liteserver'е",
+				zap.String("address", event.AccountAddress),
+				zap.Uint32("seqno", event.Seqno),
+				zap.Int("attempt", attempt),
+				zap.Error(err),
+			)
+			rec.Decide(trace.VerdictDeferred, "", "аккаунт не виден на liteserver'е")
+			p.scheduleRecheck(ctx, event, attempt, err)
+		} else if parent.Err() != nil {
+			// Проверку прервала остановка: деплой не теряем, проверим после перезапуска
+			rec.Decide(trace.VerdictDeferred, "", "проверка прервана остановкой")
+			p.scheduleRecheck(ctx, event, attempt, err)
+		} else {
 			p.logger.Debug("не удалось получить code_hash",
 				zap.String("address", event.AccountAddress),
 				zap.Error(err),
 			)
-			return nil
+			rec.Decide(trace.VerdictError, "", "не удалось получить code_hash")
 		}
-		codeHash = ch
+		p.finish(parent, job)
+		return
+	}
+	rec.SetCodeHash(codeHash)
+	rec.Step("code_hash", started, fmt.Sprintf("seqno %d", ref.Seqno), nil)
+	if ref.Seqno != 0 {
+		ctx = ton.WithBlock(ctx, ref)
 	}
 
-	// ГЛАВНАЯ ПРОВЕРКА: Верификация по интерфейсу И/ИЛИ code_hash
-	// Используем VerifyAndInspect который проверяет get_jetton_data
-	meta, err := p.detector.VerifyAndInspect(ctx, event.AccountAddress, codeHash)
-	if err != nil {
-		if err == detector.ErrNotJettonMinter {
-			// Это не Jetton Minter — пропускаем молча
-			return nil
+	// ГЛАВНАЯ ПРОВЕРКА: цепочка детекторов (Jetton Minter проверяется первым)
+	target := detector.Target{Address: event.AccountAddress, CodeHash: codeHash, Code: event.Code}
+
+	// Для неизвестного кода снимаем fingerprint интерфейсов (кэшируется по code_hash)
+	if p.prints != nil && !p.detector.IsKnownCodeHash(codeHash) {
+		started := time.Now()
+		target.Interfaces = p.prints.Fingerprint(ctx, target)
+		result := strings.Join(target.Interfaces, ",")
+		if target.Interfaces == nil {
+			result = "incomplete"
 		}
-		p.logger.Warn("ошибка верификации минтера",
-			zap.String("address", event.AccountAddress),
-			zap.Error(err),
-		)
-		return nil
+		rec.Step("fingerprint", started, result, nil)
+	}
+
+	cls, transient := p.classify(ctx, target)
+	if cls == nil && transient != nil {
+		// Временный сбой проверки — в очередь повторов; правила проверим на повторе
+		rec.Decide(trace.VerdictDeferred, "", "временный сбой детектора")
+		p.scheduleRecheck(ctx, event, attempt, transient)
+		p.finish(parent, job)
+		return
+	}
+
+	// Правила из конфига видят любой деплой, в том числе не распознанный детекторами
+	if p.rules != nil {
+		p.matchRules(ctx, event, target, cls)
+	}
+
+	job.settled = true
+	if cls == nil {
+		rec.Decide(trace.VerdictRejected, "", "ни один детектор не подошёл")
+		p.finish(parent, job)
+		return
 	}
 
-	p.totalDetected++
+	atomic.AddInt64(&p.totalDetected, 1)
+	rec.Decide(trace.VerdictAccepted, string(cls.Kind), "распознан детектором "+cls.Detector)
+	job.ref, job.cls = ref, cls
+
+	if p.pipeline != nil {
+		p.pipeline.enrich.push(parent, job)
+		return
+	}
+	p.enrich(parent, job)
+}
+
+// enrich дополняет распознанный контракт (архив кода, риск, репутация деплоера) и отправляет его.
+// Получает собственный дедлайн timeouts.verify; запросы идут на блоке, где виден аккаунт.
+func (p *Processor) enrich(parent context.Context, job *deployJob) {
+	parent = trace.WithRecorder(parent, job.rec)
+	defer p.finish(parent, job)
+
+	ctx, cancel := context.WithTimeout(parent, p.timeouts.Verify)
+	defer cancel()
+	if job.ref.Seqno != 0 {
+		ctx = ton.WithBlock(ctx, job.ref)
+	}
+
+	cls, event := job.cls, job.event
+
+	// Код неизвестного контракта сохраняем до того, как его hash попадёт в каталог
+	if !p.detector.IsKnownCodeHash(cls.CodeHash) {
+		p.archiveCode(cls, event)
+	}
+
+	if cls.Kind == detector.KindJettonMinter {
+		if job.attempt > 0 {
+			cls.Jetton.LateVerified = true
+		}
+		p.handleJetton(ctx, cls.Jetton, event)
+		return
+	}
+
+	p.handleContract(ctx, cls, event)
+	if cls.Kind == detector.KindDexPool {
+		p.handlePool(ctx, cls, event)
+	}
+}
+
+// finish завершает деплой: освобождает адрес, если решение не принято, и сохраняет трассировку.
+func (p *Processor) finish(ctx context.Context, job *deployJob) {
+	if job.claimed && !job.settled {
+		p.release(ctx, job.event.AccountAddress)
+	}
+	p.finishTrace(job.rec)
+}
+
+// abandon завершает деплой, отброшенный переполненной очередью стадии или остановкой конвейера.
+func (p *Processor) abandon(job *deployJob) {
+	reason := "очередь конвейера переполнена"
+	if p.pipeline.stopped() {
+		reason = "конвейер остановлен"
+	}
+	job.settled = false
+	job.rec.Decide(trace.VerdictError, "", reason)
+	p.finish(context.Background(), job)
+}
+
+// waitForAccount находит блок мастерчейна, на котором виден задеплоенный аккаунт.
+// Начинает с блока деплоя (event.Seqno) и при ErrNotReady переходит к следующим
+// с паузами accountWaitBackoff. Возвращает code_hash на найденном блоке.
+// Без seqno в событии используется последний блок (нулевой BlockRef).
+func (p *Processor) waitForAccount(parent context.Context, event ton.Event) (string, ton.BlockRef, error) {
+	ctx, cancel := context.WithTimeout(parent, p.timeouts.AccountWait)
+	defer cancel()
+
+	if event.Seqno == 0 {
+		if event.CodeHash != "" {
+			return event.CodeHash, ton.BlockRef{}, nil
+		}
+		codeHash, err := p.client.GetCodeHash(ctx, event.AccountAddress)
+		return codeHash, ton.BlockRef{}, err
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= accountWaitBlocks; attempt++ {
+		if delay := accountWaitBackoff[attempt]; delay > 0 {
+			select {
+			case <-ctx.Done():
+				return "", ton.BlockRef{}, fmt.Errorf("%w: %v", ton.ErrNotReady, ctx.Err())
+			case <-time.After(delay):
+			}
+		}
+
+		ref := ton.BlockRef{Seqno: event.Seqno + uint32(attempt)}
+		codeHash, err := p.client.GetCodeHashAt(ctx, ref, event.AccountAddress)
+		if err == nil {
+			if attempt > 0 {
+				p.logger.Debug("аккаунт появился на следующем блоке",
+					zap.String("address", event.AccountAddress),
+					zap.Uint32("deploy_seqno", event.Seqno),
+					zap.Uint32("seqno", ref.Seqno),
+				)
+			}
+			return codeHash, ref, nil
+		}
+		if !errors.Is(err, ton.ErrNotReady) {
+			return "", ton.BlockRef{}, err
+		}
+		lastErr = err
+	}
+
+	return "", ton.BlockRef{}, lastErr
+}
+
+// classify прогоняет контракт через цепочку детекторов.
+// Если ни один не подошёл, возвращает временную ошибку детектора (detector.ErrTransient), если она была.
+func (p *Processor) classify(ctx context.Context, target detector.Target) (*detector.Classification, error) {
+	var transient error
+	for _, cd := range p.detectors {
+		started := time.Now()
+		cls, err := cd.Detect(ctx, target)
+		trace.Record(ctx, "detector:"+cd.Name(), started, detectResult(err), err)
+		if err != nil {
+			if errors.Is(err, detector.ErrTransient) {
+				transient = err
+				continue
+			}
+			if !errors.Is(err, detector.ErrNotMatched) {
+				p.logger.Warn("ошибка детектора",
+					zap.String("detector", cd.Name()),
+					zap.String("address", target.Address),
+					zap.Error(err),
+				)
+			}
+			continue
+		}
+		return cls, nil
+	}
+	return nil, transient
+}
+
+// handleJetton обогащает и отправляет найденный Jetton Minter.
+func (p *Processor) handleJetton(ctx context.Context, meta *detector.Metadata, event ton.Event) {
+	// Первичный mint в том же сообщении, что и деплой: получатель и объём supply без get-методов.
+	// Нужен до оценки риска: по нему видно, что supply ушёл деплоеру
+	if op := event.Op; op != nil && op.Kind == ton.OpMint && !op.Aborted {
+		meta.InitialMint = op
+	}
+
+	// Оценка риска скама/honeypot для SafetyChecker бота
+	if p.risk != nil {
+		p.risk.Assess(ctx, meta, event.Deployer)
+	}
+
+	// Подделка USDT/NOT/STON и других защищённых токенов (name/symbol уже дополнены из JSON)
+	if p.imposters != nil {
+		p.imposters.Check(meta)
+	}
+
+	// История деплоера: серийные скамеры, конвейеры, списки allow/block
+	if p.deployers != nil {
+		p.deployers.Record(ctx, meta, event.Deployer)
+	}
+
+	// Админ нового минтера под наблюдением
+	if p.watchlist != nil {
+		if matches := p.watchlist.MatchAddress(watchlist.FieldAdmin, meta.AdminAddr); len(matches) > 0 {
+			p.watch(ctx, event, string(detector.KindJettonMinter), matches)
+		}
+	}
+
+	p.minters.add(meta.Address, meta.Decimals, meta.InitialMint != nil, time.Now())
 
 	// Вычисляем общую задержку обнаружения
 	totalLatencyMs := time.Since(event.Timestamp).Milliseconds()
@@ -107,16 +599,42 @@ func (p *Processor) Handle(event ton.Event) error {
 		zap.String("type", meta.MinterType),
 		zap.Bool("known_code_hash", meta.KnownCodeHash),
 		zap.Bool("verified_by_interface", meta.VerifiedByInterface),
+		zap.Bool("late_verified", meta.LateVerified),
+		zap.String("code_family", meta.CodeFamily),
+		zap.Float64("code_similarity", meta.CodeSimilarity),
+		zap.Int("risk_score", meta.RiskScore),
+		zap.Strings("risk_reasons", meta.RiskReasons),
 		zap.Int64("latency_ms", totalLatencyMs),
 		zap.Int32("workchain", event.Workchain),
 		zap.Uint32("seqno", event.Seqno),
 	)
 
 	// Запоминаем адрес в кэше
-	if p.cache != nil {
-		if err := p.cache.RememberMinter(ctx, meta.Address); err != nil {
-			p.logger.Warn("не удалось сохранить минтер в кэш", zap.Error(err))
+	p.remember(ctx, meta.Address)
+
+	// Деплоер в блок-листе: минтер запомнен, но уведомление не отправляем
+	if meta.Deployer != nil && meta.Deployer.List == detector.DeployerBlocked {
+		p.logger.Info("уведомление подавлено: деплоер в блок-листе",
+			zap.String("address", meta.Address),
+			zap.String("deployer", meta.Deployer.Address),
+		)
+		if rec := trace.FromContext(ctx); rec != nil {
+			rec.Decide(trace.VerdictSuppressed, string(detector.KindJettonMinter), "деплоер в блок-листе")
 		}
+		return
+	}
+
+	// Поток однотипных деплоев: вместо сообщения на каждый минтер — сводка кампании
+	if p.suppressCampaign(ctx, meta, event) {
+		return
+	}
+
+	// Дальше следим за supply, админом и content
+	if p.tracker != nil {
+		p.tracker.Track(meta)
+	}
+	if p.holders != nil {
+		p.holders.Track(meta, event.Deployer)
 	}
 
 	// Автоматически добавляем новый code_hash если верифицирован по интерфейсу
@@ -128,11 +646,161 @@ func (p *Processor) Handle(event ton.Event) error {
 	if p.notifier != nil {
 		p.notifier.NotifyWithEvent(ctx, meta, &event)
 	}
+}
 
-	return nil
+// handleContract отправляет контракт другого типа (NFT, пул, лаунчпад).
+func (p *Processor) handleContract(ctx context.Context, cls *detector.Classification, event ton.Event) {
+	p.logger.Info("найден контракт",
+		zap.String("kind", string(cls.Kind)),
+		zap.String("address", cls.Address),
+		zap.String("code_hash", cls.CodeHash),
+		zap.Strings("interfaces", cls.Interfaces),
+		zap.Int64("latency_ms", time.Since(event.Timestamp).Milliseconds()),
+		zap.Uint32("seqno", event.Seqno),
+	)
+
+	p.remember(ctx, cls.Address)
+
+	if p.notifier != nil {
+		p.notifier.NotifyContract(ctx, cls, &event)
+	}
+}
+
+// watch отправляет совпадение со списком наблюдения отдельным высокоприоритетным маршрутом.
+func (p *Processor) watch(ctx context.Context, event ton.Event, kind string, matches []watchlist.Match) {
+	fields := make([]string, 0, len(matches))
+	for _, m := range matches {
+		fields = append(fields, m.Field+"="+m.Value)
+	}
+	p.logger.Info("👁 событие из списка наблюдения",
+		zap.String("address", event.AccountAddress),
+		zap.Bool("deploy", event.IsDeploy),
+		zap.String("kind", kind),
+		zap.Strings("matches", fields),
+		zap.String("tx_hash", event.TxHash),
+	)
+
+	if p.notifier != nil {
+		p.notifier.NotifyWatch(ctx, &watchlist.Hit{
+			Address:      event.AccountAddress,
+			IsDeploy:     event.IsDeploy,
+			ContractKind: kind,
+			Matches:      matches,
+		}, &event)
+	}
+}
+
+// archiveCode сохраняет StateInit контракта в архив (только первое появление code_hash).
+func (p *Processor) archiveCode(cls *detector.Classification, event ton.Event) {
+	if p.archive == nil || len(event.Code) == 0 {
+		return
+	}
+
+	entry := archive.Entry{
+		CodeHash:     cls.CodeHash,
+		Kind:         string(cls.Kind),
+		FirstAddress: cls.Address,
+		Seqno:        event.Seqno,
+		TxHash:       event.TxHash,
+		TxLT:         event.TxLT,
+		FirstSeen:    event.Timestamp.UTC(),
+	}
+	if cls.Jetton != nil {
+		entry.MinterType = cls.Jetton.MinterType
+	}
+
+	created, err := p.archive.Store(entry, event.Code, event.Data)
+	if err != nil {
+		p.logger.Warn("не удалось сохранить код в архив",
+			zap.String("code_hash", cls.CodeHash),
+			zap.Error(err),
+		)
+		return
+	}
+	if created {
+		p.logger.Info("код контракта сохранён в архив",
+			zap.String("code_hash", cls.CodeHash),
+			zap.String("kind", string(cls.Kind)),
+			zap.String("address", cls.Address),
+		)
+	}
+}
+
+// finishTrace сохраняет решение по деплою в хранилище трассировки.
+func (p *Processor) finishTrace(rec *trace.Recorder) {
+	if p.decisions == nil {
+		return
+	}
+
+	d := rec.Finish()
+	if d.Verdict != trace.VerdictAccepted {
+		p.logger.Debug("деплой отклонён",
+			zap.String("address", d.Address),
+			zap.String("verdict", string(d.Verdict)),
+			zap.String("reason", d.Reason),
+		)
+	}
+	p.decisions.Add(d)
+}
+
+func detectResult(err error) string {
+	switch {
+	case err == nil:
+		return "matched"
+	case errors.Is(err, detector.ErrNotMatched):
+		return "not_matched"
+	case errors.Is(err, detector.ErrTransient):
+		return "transient"
+	default:
+		return "error"
+	}
+}
+
+func hitString(hit bool) string {
+	if hit {
+		return "hit"
+	}
+	return "miss"
+}
+
+// remember помечает занятый адрес как обработанный. Как и release, получает собственный дедлайн:
+// обогащение могло исчерпать дедлайн проверки, а неподтверждённый адрес после аренды проверят снова.
+func (p *Processor) remember(ctx context.Context, address string) {
+	if p.cache == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), claimTimeout)
+	defer cancel()
+	if err := p.cache.ConfirmMinter(ctx, address); err != nil {
+		p.logger.Warn("не удалось сохранить минтер в кэш", zap.Error(err))
+	}
+}
+
+// release освобождает адрес после отложенной или неудачной проверки. Дедлайн проверки
+// к этому моменту мог истечь, поэтому освобождение получает собственный.
+func (p *Processor) release(ctx context.Context, address string) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), claimTimeout)
+	defer cancel()
+	if err := p.cache.ReleaseMinter(ctx, address); err != nil {
+		p.logger.Warn("не удалось освободить адрес в кэше", zap.String("address", address), zap.Error(err))
+	}
+}
+
+// claimLease — аренда адреса на время проверки: с запасом покрывает ожидание аккаунта и проверку.
+func (p *Processor) claimLease() time.Duration {
+	lease := 2 * (p.timeouts.AccountWait + p.timeouts.Verify)
+	if lease < minClaimLease {
+		lease = minClaimLease
+	}
+	return lease
 }
 
 // GetStats возвращает статистику обработки.
 func (p *Processor) GetStats() (processed, detected int64) {
-	return p.totalProcessed, p.totalDetected
+	return atomic.LoadInt64(&p.totalProcessed), atomic.LoadInt64(&p.totalDetected)
+}
+
+// NotReadyCount возвращает число событий, для которых аккаунт так и не появился.
+func (p *Processor) NotReadyCount() int64 {
+	return atomic.LoadInt64(&p.totalNotReady)
 }
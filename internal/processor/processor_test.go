@@ -2,74 +2,156 @@ package processor
 
 import (
 	"context"
-	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/xssnick/tonutils-go/address"
+	"github.com/xssnick/tonutils-go/tvm/cell"
+	"github.com/yourname/hyper-sniper-indexer/internal/archive"
 	"github.com/yourname/hyper-sniper-indexer/internal/detector"
+	"github.com/yourname/hyper-sniper-indexer/internal/rules"
+	"github.com/yourname/hyper-sniper-indexer/internal/storage"
+	"github.com/yourname/hyper-sniper-indexer/internal/trace"
+	"github.com/yourname/hyper-sniper-indexer/internal/watchlist"
 	"github.com/yourname/hyper-sniper-indexer/pkg/ton"
 	"go.uber.org/zap"
 )
 
-type testCache struct {
-	minters map[string]bool
+// notifierStub вызывается из воркеров доставки конвейера, поэтому поля защищены mu.
+type notifierStub struct {
+	mu        sync.Mutex
+	count     int
+	last      *detector.Metadata
+	contracts []*detector.Classification
+	watches   []*watchlist.Hit
+	ops       []*ton.JettonEvent
+	pools     []*detector.PoolEvent
+	rules     []*rules.Hit
+	campaigns []*detector.Campaign
 }
 
-func (c *testCache) RegisterSeqno(context.Context, uint32) (bool, error) { return true, nil }
-func (c *testCache) IsMinterKnown(_ context.Context, address string) (bool, error) {
-	return c.minters[address], nil
+func (n *notifierStub) NotifyWithEvent(_ context.Context, meta *detector.Metadata, _ *ton.Event) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.count++
+	n.last = meta
 }
-func (c *testCache) RememberMinter(_ context.Context, address string) error {
-	if c.minters == nil {
-		c.minters = make(map[string]bool)
-	}
-	c.minters[address] = true
-	return nil
+
+func (n *notifierStub) NotifyContract(_ context.Context, cls *detector.Classification, _ *ton.Event) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.contracts = append(n.contracts, cls)
 }
 
-type notifierStub struct {
-	count int
+func (n *notifierStub) NotifyWatch(_ context.Context, hit *watchlist.Hit, _ *ton.Event) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.watches = append(n.watches, hit)
 }
 
-func (n *notifierStub) Notify(context.Context, *detector.Metadata) {
-	n.count++
+func (n *notifierStub) NotifyJettonOp(_ context.Context, ev *ton.JettonEvent) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.ops = append(n.ops, ev)
+}
+
+func (n *notifierStub) NotifyPool(_ context.Context, ev *detector.PoolEvent) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.pools = append(n.pools, ev)
+}
+
+func (n *notifierStub) NotifyRule(_ context.Context, hit *rules.Hit, _ *ton.Event) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.rules = append(n.rules, hit)
+}
+
+func (n *notifierStub) NotifyCampaign(_ context.Context, camp *detector.Campaign) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.campaigns = append(n.campaigns, camp)
 }
 
+// tonClientStub вызывается параллельно из проб fingerprinting, поэтому blocks и errs защищены mu.
 type tonClientStub struct {
-	stack [][]byte
+	mu       sync.Mutex
+	stacks   map[string][][]byte
+	errs     map[string]error
+	readyAt  uint32        // первый seqno, на котором виден аккаунт
+	codeHash string        // code_hash вместо известного hash минтера
+	delay    time.Duration // задержка GetCodeHash
+	blocks   []uint32      // блоки, на которых вызывались get-методы
 }
 
-func (t *tonClientStub) Start(context.Context) error                                  { return nil }
-func (t *tonClientStub) Subscribe(context.Context, ton.Handler) error                 { return nil }
-func (t *tonClientStub) Catchup(context.Context, time.Time, ton.Handler) error        { return nil }
-func (t *tonClientStub) RunGetMethod(context.Context, string, string, ...any) ([][]byte, error) {
-	return t.stack, nil
+func (t *tonClientStub) Start(context.Context) error                           { return nil }
+func (t *tonClientStub) Subscribe(context.Context, ton.Handler) error          { return nil }
+func (t *tonClientStub) Catchup(context.Context, time.Time, ton.Handler) error { return nil }
+func (t *tonClientStub) RunGetMethod(ctx context.Context, _ string, method string, _ ...any) ([][]byte, error) {
+	ref, _ := ton.BlockFromContext(ctx)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.blocks = append(t.blocks, ref.Seqno)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if err := t.errs[method]; err != nil {
+		return nil, err
+	}
+	return t.stacks[method], nil
 }
-func (t *tonClientStub) GetCodeHash(context.Context, string) (string, error) {
+func (t *tonClientStub) GetCodeHash(ctx context.Context, _ string) (string, error) {
+	if t.delay > 0 {
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(t.delay):
+		}
+	}
+	if t.codeHash != "" {
+		return t.codeHash, nil
+	}
 	return "6d9f5c5d5e6f7a8b9c0d1e2f3a4b5c6d7e8f9a0b1c2d3e4f5a6b7c8d9e0f1a2b", nil
 }
+func (t *tonClientStub) RunGetMethodAt(ctx context.Context, ref ton.BlockRef, addr string, method string, args ...any) ([][]byte, error) {
+	return t.RunGetMethod(ton.WithBlock(ctx, ref), addr, method, args...)
+}
+func (t *tonClientStub) GetCodeHashAt(ctx context.Context, ref ton.BlockRef, addr string) (string, error) {
+	if ref.Seqno < t.readyAt {
+		return "", ton.ErrNotReady
+	}
+	return t.GetCodeHash(ctx, addr)
+}
+
+func jettonDataStack() [][]byte {
+	admin := address.NewAddress(0, 0, make([]byte, 32))
+	return [][]byte{
+		big.NewInt(1_000_000).Bytes(),
+		big.NewInt(1).Bytes(),
+		cell.BeginCell().MustStoreAddr(admin).EndCell().ToBOC(),
+		cell.BeginCell().MustStoreUInt(0x00, 8).MustStoreDict(nil).EndCell().ToBOC(),
+	}
+}
 
 func TestProcessorHandleTriggersNotifier(t *testing.T) {
 	logger := zap.NewNop()
 
-	dec := make([]byte, 8)
-	binary.BigEndian.PutUint64(dec, 6)
-
 	client := &tonClientStub{
-		stack: [][]byte{
-			[]byte("Name"),
-			[]byte("SYM"),
-			dec,
-		},
+		stacks: map[string][][]byte{"get_jetton_data": jettonDataStack()},
 	}
 
 	det := detector.NewDetector(client, logger)
-	cache := &testCache{minters: make(map[string]bool)}
+	cache := storage.NewMemoryCache(time.Hour)
 	notifier := &notifierStub{}
 
 	proc := NewProcessor(det, client, cache, notifier, logger)
 
-	err := proc.Handle(ton.Event{
+	err := proc.Handle(context.Background(), ton.Event{
 		AccountAddress: "0:abcdef",
 		CodeHash:       "",
 		Timestamp:      time.Now(),
@@ -80,7 +162,7 @@ func TestProcessorHandleTriggersNotifier(t *testing.T) {
 		t.Fatalf("handle returned error: %v", err)
 	}
 
-	if !cache.minters["0:abcdef"] {
+	if known, _ := cache.IsMinterKnown(context.Background(), "0:abcdef"); !known {
 		t.Fatalf("minter was not cached")
 	}
 
@@ -89,3 +171,936 @@ func TestProcessorHandleTriggersNotifier(t *testing.T) {
 	}
 }
 
+// deadlineCache — MemoryCache, который, как Redis, не выполняет операции с истёкшим ctx.
+type deadlineCache struct {
+	*storage.MemoryCache
+}
+
+func (c deadlineCache) ConfirmMinter(ctx context.Context, address string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return c.MemoryCache.ConfirmMinter(ctx, address)
+}
+
+func TestProcessorRememberAfterVerifyDeadline(t *testing.T) {
+	cache := storage.NewMemoryCache(time.Hour)
+	proc := NewProcessor(nil, nil, deadlineCache{cache}, nil, zap.NewNop())
+
+	lease := 20 * time.Millisecond
+	if claimed, err := cache.ClaimMinter(context.Background(), "0:late", lease); err != nil || !claimed {
+		t.Fatalf("claim failed: %v %v", claimed, err)
+	}
+
+	// Дедлайн проверки истёк во время обогащения: подтверждение всё равно сохраняется
+	verifyCtx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	<-verifyCtx.Done()
+	proc.remember(verifyCtx, "0:late")
+
+	// Аренда проверки истекла: адрес занят только подтверждением
+	time.Sleep(2 * lease)
+	if known, _ := cache.IsMinterKnown(context.Background(), "0:late"); !known {
+		t.Fatalf("minter was not confirmed after verify deadline")
+	}
+}
+
+func TestProcessorCollapsesSpamCampaign(t *testing.T) {
+	logger := zap.NewNop()
+
+	client := &tonClientStub{stacks: map[string][][]byte{"get_jetton_data": jettonDataStack()}}
+	notifier := &notifierStub{}
+	proc := NewProcessor(detector.NewDetector(client, logger), client, storage.NewMemoryCache(time.Hour), notifier, logger)
+	proc.SetCampaigns(detector.NewCampaignDetector(time.Minute, 2, nil, logger))
+
+	// Все три минтера от одного деплоера — фабрика
+	for _, addr := range []string{"0:a1", "0:a2", "0:a3"} {
+		event := ton.Event{AccountAddress: addr, Deployer: "0:factory", Timestamp: time.Now(), IsDeploy: true}
+		if err := proc.Handle(context.Background(), event); err != nil {
+			t.Fatalf("handle returned error: %v", err)
+		}
+	}
+
+	if notifier.count != 1 || len(notifier.campaigns) != 1 {
+		t.Fatalf("expected one minter and one campaign notification, got minters=%d campaigns=%d", notifier.count, len(notifier.campaigns))
+	}
+	if camp := notifier.campaigns[0]; camp.By != detector.CampaignByDeployer || camp.Count != 2 || camp.Sample[1] != "0:a2" || camp.Finished {
+		t.Fatalf("unexpected campaign: %+v", camp)
+	}
+}
+
+// nopDeployerHistory — история деплоеров без записей.
+type nopDeployerHistory struct{}
+
+func (nopDeployerHistory) AppendDeployment(context.Context, string, []byte) error { return nil }
+func (nopDeployerHistory) Deployments(context.Context, string) ([][]byte, error)  { return nil, nil }
+
+func TestProcessorCampaignSkipsAllowedDeployer(t *testing.T) {
+	logger := zap.NewNop()
+
+	launchpad := "0:" + strings.Repeat("1a", 32)
+	client := &tonClientStub{stacks: map[string][][]byte{"get_jetton_data": jettonDataStack()}}
+	notifier := &notifierStub{}
+	proc := NewProcessor(detector.NewDetector(client, logger), client, storage.NewMemoryCache(time.Hour), notifier, logger)
+	proc.SetDeployerReputation(detector.NewDeployerReputation(nopDeployerHistory{}, []string{launchpad}, nil, logger))
+	proc.SetCampaigns(detector.NewCampaignDetector(time.Minute, 2, nil, logger))
+
+	// Доверенный лаунчпад деплоит подряд: каждый минтер уведомляется
+	for _, addr := range []string{"0:l1", "0:l2", "0:l3"} {
+		event := ton.Event{AccountAddress: addr, Deployer: launchpad, Timestamp: time.Now(), IsDeploy: true}
+		if err := proc.Handle(context.Background(), event); err != nil {
+			t.Fatalf("handle returned error: %v", err)
+		}
+	}
+
+	if notifier.count != 3 || len(notifier.campaigns) != 0 {
+		t.Fatalf("allowed deployer collapsed into campaign: minters=%d campaigns=%d", notifier.count, len(notifier.campaigns))
+	}
+}
+
+func TestProcessorCampaignUsesBlockTime(t *testing.T) {
+	logger := zap.NewNop()
+
+	client := &tonClientStub{stacks: map[string][][]byte{"get_jetton_data": jettonDataStack()}}
+	notifier := &notifierStub{}
+	proc := NewProcessor(detector.NewDetector(client, logger), client, storage.NewMemoryCache(time.Hour), notifier, logger)
+	proc.SetCampaigns(detector.NewCampaignDetector(time.Minute, 2, nil, logger))
+
+	// Catchup: деплои одного деплоера с интервалом в час приходят подряд
+	start := time.Now().Add(-3 * time.Hour)
+	for i, addr := range []string{"0:c1", "0:c2", "0:c3"} {
+		event := ton.Event{AccountAddress: addr, Deployer: "0:factory", Timestamp: start.Add(time.Duration(i) * time.Hour), IsDeploy: true}
+		if err := proc.Handle(context.Background(), event); err != nil {
+			t.Fatalf("handle returned error: %v", err)
+		}
+	}
+
+	if notifier.count != 3 || len(notifier.campaigns) != 0 {
+		t.Fatalf("replayed deploys collapsed into campaign: minters=%d campaigns=%d", notifier.count, len(notifier.campaigns))
+	}
+}
+
+func TestProcessorHandleClassifiesNFTCollection(t *testing.T) {
+	logger := zap.NewNop()
+
+	owner := address.NewAddress(0, 0, make([]byte, 32))
+	client := &tonClientStub{
+		stacks: map[string][][]byte{"get_collection_data": {
+			big.NewInt(5).Bytes(),
+			cell.BeginCell().MustStoreUInt(0x01, 8).MustStoreStringSnake("https://example.com/c.json").EndCell().ToBOC(),
+			cell.BeginCell().MustStoreAddr(owner).EndCell().ToBOC(),
+		}},
+	}
+
+	notifier := &notifierStub{}
+	proc := NewProcessor(detector.NewDetector(client, logger), client, storage.NewMemoryCache(time.Hour), notifier, logger)
+
+	if err := proc.Handle(context.Background(), ton.Event{AccountAddress: "0:nft", Timestamp: time.Now(), IsDeploy: true}); err != nil {
+		t.Fatalf("handle returned error: %v", err)
+	}
+
+	if notifier.count != 0 || len(notifier.contracts) != 1 {
+		t.Fatalf("expected one contract notification, got jetton=%d contracts=%d", notifier.count, len(notifier.contracts))
+	}
+
+	cls := notifier.contracts[0]
+	if cls.Kind != detector.KindNFTCollection || cls.NFT.NextItemIndex != "5" {
+		t.Fatalf("unexpected classification: %+v", cls)
+	}
+}
+
+func TestProcessorHandleWaitsForAccount(t *testing.T) {
+	logger := zap.NewNop()
+
+	backoff := accountWaitBackoff
+	accountWaitBackoff = []time.Duration{0, time.Millisecond, time.Millisecond, time.Millisecond}
+	defer func() { accountWaitBackoff = backoff }()
+
+	client := &tonClientStub{
+		stacks:  map[string][][]byte{"get_jetton_data": jettonDataStack()},
+		readyAt: 11,
+	}
+	notifier := &notifierStub{}
+	proc := NewProcessor(detector.NewDetector(client, logger), client, storage.NewMemoryCache(time.Hour), notifier, logger)
+
+	if err := proc.Handle(context.Background(), ton.Event{AccountAddress: "0:late", Timestamp: time.Now(), Seqno: 10, IsDeploy: true}); err != nil {
+		t.Fatalf("handle returned error: %v", err)
+	}
+	if notifier.count != 1 {
+		t.Fatalf("notifier should be called once, got %d", notifier.count)
+	}
+	for _, seqno := range client.blocks {
+		if seqno != 11 {
+			t.Fatalf("get method called outside of block 11: %v", client.blocks)
+		}
+	}
+
+	// Аккаунт так и не появился — событие учитывается, а не теряется молча
+	client.readyAt = 100
+	if err := proc.Handle(context.Background(), ton.Event{AccountAddress: "0:never", Timestamp: time.Now(), Seqno: 10, IsDeploy: true}); err != nil {
+		t.Fatalf("handle returned error: %v", err)
+	}
+	if proc.NotReadyCount() != 1 || notifier.count != 1 {
+		t.Fatalf("unexpected result: not_ready=%d notified=%d", proc.NotReadyCount(), notifier.count)
+	}
+}
+
+func TestProcessorVerifyDeadlineStartsAfterAccountWait(t *testing.T) {
+	logger := zap.NewNop()
+
+	backoff := accountWaitBackoff
+	accountWaitBackoff = []time.Duration{0, 20 * time.Millisecond, 20 * time.Millisecond, 20 * time.Millisecond}
+	defer func() { accountWaitBackoff = backoff }()
+
+	// Аккаунт виден только на четвёртом блоке: ожидание дольше дедлайна проверки
+	client := &tonClientStub{
+		stacks:  map[string][][]byte{"get_jetton_data": jettonDataStack()},
+		readyAt: 13,
+	}
+	notifier := &notifierStub{}
+	proc := NewProcessor(detector.NewDetector(client, logger), client, storage.NewMemoryCache(time.Hour), notifier, logger)
+	proc.SetFingerprinter(nil)
+	proc.SetTimeouts(Timeouts{Verify: 50 * time.Millisecond})
+
+	if err := proc.Handle(context.Background(), ton.Event{AccountAddress: "0:slow", Timestamp: time.Now(), Seqno: 10, IsDeploy: true}); err != nil {
+		t.Fatalf("handle returned error: %v", err)
+	}
+	if notifier.count != 1 {
+		t.Fatalf("minter found after account wait was not notified, got %d", notifier.count)
+	}
+}
+
+type memoryRecheckQueue struct {
+	mu      sync.Mutex
+	pending [][]byte
+}
+
+func (q *memoryRecheckQueue) ScheduleRecheck(ctx context.Context, payload []byte, _ time.Time) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.pending = append(q.pending, payload)
+	return nil
+}
+
+func (q *memoryRecheckQueue) DueRechecks(_ context.Context, _ time.Time, limit int) ([][]byte, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	n := min(limit, len(q.pending))
+	due := q.pending[:n:n]
+	q.pending = q.pending[n:]
+	return due, nil
+}
+
+func TestProcessorRechecksRunInParallel(t *testing.T) {
+	logger := zap.NewNop()
+
+	client := &tonClientStub{
+		stacks: map[string][][]byte{"get_jetton_data": jettonDataStack()},
+		delay:  300 * time.Millisecond,
+	}
+	notifier := &notifierStub{}
+	proc := NewProcessor(detector.NewDetector(client, logger), client, storage.NewMemoryCache(time.Hour), notifier, logger)
+	proc.SetFingerprinter(nil)
+	proc.SetTimeouts(Timeouts{Verify: 2 * time.Second})
+
+	queue := &memoryRecheckQueue{}
+	proc.SetRecheckQueue(queue)
+	for i := 0; i < 4; i++ {
+		payload, _ := json.Marshal(recheckItem{Event: ton.Event{AccountAddress: fmt.Sprintf("0:rp%d", i), IsDeploy: true}, Attempt: 1})
+		queue.pending = append(queue.pending, payload)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		proc.RunRechecks(ctx)
+		close(done)
+	}()
+
+	// Последовательно 4 × 300ms заняли бы больше секунды после первого опроса
+	deadline := time.Now().Add(recheckPollInterval + 600*time.Millisecond)
+	for {
+		notifier.mu.Lock()
+		count := notifier.count
+		notifier.mu.Unlock()
+		if count == 4 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("rechecks ran serially: %d of 4 done", count)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+	<-done
+}
+
+func TestProcessorRecheckInterruptedByShutdownIsRescheduled(t *testing.T) {
+	logger := zap.NewNop()
+
+	client := &tonClientStub{stacks: map[string][][]byte{}, delay: time.Second}
+	proc := NewProcessor(detector.NewDetector(client, logger), client, storage.NewMemoryCache(time.Hour), &notifierStub{}, logger)
+	queue := &memoryRecheckQueue{}
+	proc.SetRecheckQueue(queue)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	proc.process(ctx, ton.Event{AccountAddress: "0:stop", IsDeploy: true}, 1)
+
+	if len(queue.pending) != 1 {
+		t.Fatalf("interrupted recheck must be rescheduled, pending %d", len(queue.pending))
+	}
+}
+
+func TestProcessorRechecksTransientFailure(t *testing.T) {
+	logger := zap.NewNop()
+
+	client := &tonClientStub{
+		stacks: map[string][][]byte{"get_jetton_data": jettonDataStack()},
+		errs:   map[string]error{"get_jetton_data": ton.ErrNotReady},
+	}
+	notifier := &notifierStub{}
+	queue := &memoryRecheckQueue{}
+
+	proc := NewProcessor(detector.NewDetector(client, logger), client, storage.NewMemoryCache(time.Hour), notifier, logger)
+	proc.SetRecheckQueue(queue)
+
+	if err := proc.Handle(context.Background(), ton.Event{AccountAddress: "0:slow", Timestamp: time.Now(), Seqno: 10, IsDeploy: true}); err != nil {
+		t.Fatalf("handle returned error: %v", err)
+	}
+	if notifier.count != 0 || len(queue.pending) != 1 {
+		t.Fatalf("expected one scheduled recheck, got notified=%d queued=%d", notifier.count, len(queue.pending))
+	}
+
+	// Аккаунт стал доступен — повторная проверка находит минтер
+	client.errs = nil
+	ctx, cancel := context.WithTimeout(context.Background(), 2*recheckPollInterval)
+	defer cancel()
+	proc.RunRechecks(ctx)
+
+	if notifier.count != 1 || !notifier.last.LateVerified {
+		t.Fatalf("expected late verified notification, got %d", notifier.count)
+	}
+}
+
+func TestProcessorRechecksAccountNotReady(t *testing.T) {
+	logger := zap.NewNop()
+
+	backoff := accountWaitBackoff
+	accountWaitBackoff = []time.Duration{0, 10 * time.Millisecond, 10 * time.Millisecond, 10 * time.Millisecond}
+	defer func() { accountWaitBackoff = backoff }()
+
+	// Аккаунт не виден ни на одном из проверяемых блоков, а ожидание дольше дедлайна проверки
+	client := &tonClientStub{
+		stacks:  map[string][][]byte{"get_jetton_data": jettonDataStack()},
+		readyAt: 100,
+	}
+	queue := &memoryRecheckQueue{}
+	proc := NewProcessor(detector.NewDetector(client, logger), client, storage.NewMemoryCache(time.Hour), &notifierStub{}, logger)
+	proc.SetTimeouts(Timeouts{Verify: 5 * time.Millisecond})
+	proc.SetRecheckQueue(queue)
+
+	if err := proc.Handle(context.Background(), ton.Event{AccountAddress: "0:late", Timestamp: time.Now(), Seqno: 10, IsDeploy: true}); err != nil {
+		t.Fatalf("handle returned error: %v", err)
+	}
+	if proc.NotReadyCount() != 1 || len(queue.pending) != 1 {
+		t.Fatalf("expected scheduled recheck, got not_ready=%d queued=%d", proc.NotReadyCount(), len(queue.pending))
+	}
+}
+
+func TestProcessorArchivesUnknownCode(t *testing.T) {
+	logger := zap.NewNop()
+
+	client := &tonClientStub{
+		stacks: map[string][][]byte{"get_jetton_data": jettonDataStack()},
+	}
+	arc, err := archive.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("archive: %v", err)
+	}
+
+	proc := NewProcessor(detector.NewDetector(client, logger), client, storage.NewMemoryCache(time.Hour), &notifierStub{}, logger)
+	proc.SetArchive(arc)
+
+	code := cell.BeginCell().MustStoreUInt(0xC0DE, 16).EndCell().ToBOC()
+	for _, addr := range []string{"0:first", "0:second"} {
+		err := proc.Handle(context.Background(), ton.Event{AccountAddress: addr, Timestamp: time.Now(), Seqno: 10, TxHash: "aa", IsDeploy: true, Code: code})
+		if err != nil {
+			t.Fatalf("handle returned error: %v", err)
+		}
+	}
+
+	entries, err := arc.List()
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("expected one archive entry, got %d (%v)", len(entries), err)
+	}
+	if e := entries[0]; e.FirstAddress != "0:first" || e.Kind != string(detector.KindJettonMinter) || e.Seqno != 10 || e.CodeSize != len(code) {
+		t.Fatalf("unexpected entry: %+v", e)
+	}
+}
+
+func TestProcessorRecordsRejectedDecision(t *testing.T) {
+	logger := zap.NewNop()
+
+	// Ни один get-метод не отвечает: контракт не подходит ни одному детектору
+	client := &tonClientStub{stacks: map[string][][]byte{}}
+	store := trace.NewStore(10)
+
+	proc := NewProcessor(detector.NewDetector(client, logger), client, storage.NewMemoryCache(time.Hour), &notifierStub{}, logger)
+	proc.SetFingerprinter(nil)
+	proc.SetDecisionStore(store)
+
+	if err := proc.Handle(context.Background(), ton.Event{AccountAddress: "0:unknown", Timestamp: time.Now(), Seqno: 10, IsDeploy: true}); err != nil {
+		t.Fatalf("handle returned error: %v", err)
+	}
+
+	decisions := store.ByAddress("0:unknown")
+	if len(decisions) != 1 {
+		t.Fatalf("expected one decision, got %d", len(decisions))
+	}
+
+	d := decisions[0]
+	if d.Verdict != trace.VerdictRejected || d.Seqno != 10 || d.CodeHash == "" {
+		t.Fatalf("unexpected decision: %+v", d)
+	}
+
+	steps := map[string]string{}
+	for _, step := range d.Steps {
+		steps[step.Name] = step.Result
+	}
+	if steps["cache"] != "miss" || steps["code_hash_lookup"] != "unknown" || steps["detector:jetton_minter"] != "not_matched" {
+		t.Fatalf("unexpected steps: %+v", d.Steps)
+	}
+	if _, ok := steps["get_jetton_data"]; !ok {
+		t.Fatalf("get_jetton_data step missing: %+v", d.Steps)
+	}
+}
+
+func TestProcessorRulesMatchUnclassifiedDeploy(t *testing.T) {
+	logger := zap.NewNop()
+
+	codeHash := strings.Repeat("ab", 32)
+	client := &tonClientStub{stacks: map[string][][]byte{}, codeHash: codeHash}
+	set, err := rules.New([]rules.Rule{
+		{Name: "wallet", Kinds: []string{rules.KindUnknown}, Workchains: []int32{0}, TgChatID: "-100"},
+		{Name: "nft", Kinds: []string{string(detector.KindNFTCollection)}},
+		{Name: "launchpad", CodeHashes: []string{strings.ToUpper(codeHash)}, Workchains: []int32{-1}},
+	})
+	if err != nil {
+		t.Fatalf("rules: %v", err)
+	}
+	if _, err := rules.New([]rules.Rule{{Name: "empty"}}); err == nil {
+		t.Fatalf("rule without conditions must be rejected")
+	}
+
+	notifier := &notifierStub{}
+	proc := NewProcessor(detector.NewDetector(client, logger), client, storage.NewMemoryCache(time.Hour), notifier, logger)
+	proc.SetFingerprinter(nil)
+	proc.SetRules(set)
+
+	event := ton.Event{AccountAddress: "0:wallet", Deployer: "0:owner", Workchain: 0, Timestamp: time.Now(), IsDeploy: true}
+	if err := proc.Handle(context.Background(), event); err != nil {
+		t.Fatalf("handle returned error: %v", err)
+	}
+
+	if len(notifier.rules) != 1 || len(notifier.contracts) != 0 {
+		t.Fatalf("expected one rule hit and no contracts, got rules=%d contracts=%d", len(notifier.rules), len(notifier.contracts))
+	}
+	hit := notifier.rules[0]
+	if hit.Rule.Name != "wallet" || hit.Rule.TgChatID != "-100" || hit.Deploy.Kind != rules.KindUnknown ||
+		hit.Deploy.CodeHash != codeHash || hit.Deploy.Deployer != "0:owner" {
+		t.Fatalf("unexpected hit: %+v %+v", hit.Rule, hit.Deploy)
+	}
+}
+
+func TestProcessorWatchlistNonJettonAndTransactions(t *testing.T) {
+	logger := zap.NewNop()
+
+	watched := "0:" + strings.Repeat("ee", 32)
+	watches := watchlist.New(nil, logger)
+	if err := watches.AddConfig(watchlist.KindAddress, watched, "serial deployer"); err != nil {
+		t.Fatalf("add config: %v", err)
+	}
+
+	// Контракт не отвечает ни на один get-метод: не jetton и не NFT
+	client := &tonClientStub{stacks: map[string][][]byte{}}
+	notifier := &notifierStub{}
+	proc := NewProcessor(detector.NewDetector(client, logger), client, storage.NewMemoryCache(time.Hour), notifier, logger)
+	proc.SetFingerprinter(nil)
+	proc.SetWatchlist(watches)
+
+	events := []ton.Event{
+		{AccountAddress: "0:contract", Deployer: watched, Timestamp: time.Now(), Seqno: 10, IsDeploy: true},
+		{AccountAddress: watched, Timestamp: time.Now(), Seqno: 11},
+		{AccountAddress: "0:other", Timestamp: time.Now(), Seqno: 11},
+	}
+	for _, event := range events {
+		if err := proc.Handle(context.Background(), event); err != nil {
+			t.Fatalf("handle returned error: %v", err)
+		}
+	}
+
+	if len(notifier.watches) != 2 || notifier.count != 0 {
+		t.Fatalf("expected two watch hits and no jetton notifications, got %d/%d", len(notifier.watches), notifier.count)
+	}
+	if hit := notifier.watches[0]; !hit.IsDeploy || hit.Matches[0].Field != watchlist.FieldDeployer {
+		t.Fatalf("unexpected deploy hit: %+v", hit)
+	}
+	if hit := notifier.watches[1]; hit.IsDeploy || hit.Matches[0].Field != watchlist.FieldAccount {
+		t.Fatalf("unexpected transaction hit: %+v", hit)
+	}
+}
+
+func TestProcessorMinterOps(t *testing.T) {
+	logger := zap.NewNop()
+
+	recipient := address.NewAddress(0, 0, make([]byte, 32))
+	transfer := cell.BeginCell().
+		MustStoreUInt(uint64(ton.OpcodeInternalTransfer), 32).
+		MustStoreUInt(0, 64).
+		MustStoreBigCoins(big.NewInt(1_000_000_000)).
+		MustStoreAddr(nil).
+		MustStoreAddr(nil).
+		EndCell()
+	mint := ton.ParseMinterOp(cell.BeginCell().
+		MustStoreUInt(uint64(ton.OpcodeMint), 32).
+		MustStoreUInt(7, 64).
+		MustStoreAddr(recipient).
+		MustStoreBigCoins(big.NewInt(50_000_000)).
+		MustStoreRef(transfer).
+		EndCell())
+	if mint == nil || mint.Kind != ton.OpMint || mint.Amount != "1000000000" || mint.Destination != ton.RawAddress(recipient) {
+		t.Fatalf("unexpected mint op: %+v", mint)
+	}
+
+	client := &tonClientStub{
+		stacks: map[string][][]byte{"get_jetton_data": jettonDataStack()},
+	}
+	notifier := &notifierStub{}
+	proc := NewProcessor(detector.NewDetector(client, logger), client, storage.NewMemoryCache(time.Hour), notifier, logger)
+
+	// Деплой без mint в сообщении: первичным станет первый mint из потока транзакций
+	err := proc.Handle(context.Background(), ton.Event{AccountAddress: "0:abcdef", Timestamp: time.Now(), Seqno: 10, IsDeploy: true})
+	if err != nil {
+		t.Fatalf("handle returned error: %v", err)
+	}
+	if notifier.count != 1 || notifier.last.InitialMint != nil {
+		t.Fatalf("expected jetton notification without initial mint")
+	}
+	if !proc.WatchesJetton("0:abcdef") || proc.WatchesJetton("0:other") {
+		t.Fatalf("only the detected minter should be watched")
+	}
+
+	events := []ton.Event{
+		{AccountAddress: "0:abcdef", Timestamp: time.Now(), Seqno: 11, Op: mint},
+		{AccountAddress: "0:abcdef", Timestamp: time.Now(), Seqno: 12, Op: mint},
+		{AccountAddress: "0:other", Timestamp: time.Now(), Seqno: 12, Op: mint},
+	}
+	for _, event := range events {
+		if err := proc.Handle(context.Background(), event); err != nil {
+			t.Fatalf("handle returned error: %v", err)
+		}
+	}
+
+	if len(notifier.ops) != 2 {
+		t.Fatalf("expected two minter ops, got %d", len(notifier.ops))
+	}
+	if !notifier.ops[0].Initial || notifier.ops[1].Initial {
+		t.Fatalf("only the first mint should be initial")
+	}
+}
+
+func TestProcessorJettonWalletOps(t *testing.T) {
+	logger := zap.NewNop()
+
+	walletOp := func(opcode uint32) *ton.JettonOp {
+		return ton.ParseWalletOp(cell.BeginCell().
+			MustStoreUInt(uint64(opcode), 32).
+			MustStoreUInt(0, 64).
+			MustStoreBigCoins(big.NewInt(500)).
+			MustStoreAddr(nil).
+			EndCell())
+	}
+
+	client := &tonClientStub{
+		stacks: map[string][][]byte{"get_jetton_data": jettonDataStack()},
+	}
+	notifier := &notifierStub{}
+	proc := NewProcessor(detector.NewDetector(client, logger), client, storage.NewMemoryCache(time.Hour), notifier, logger)
+	if err := proc.Handle(context.Background(), ton.Event{AccountAddress: "0:minter", Timestamp: time.Now(), Seqno: 10, IsDeploy: true}); err != nil {
+		t.Fatalf("handle returned error: %v", err)
+	}
+
+	events := []ton.Event{
+		// Кошелёк от минтера, затем перевод с него на второй кошелёк
+		{AccountAddress: "0:wallet1", Deployer: "0:minter", Timestamp: time.Now(), Seqno: 11, Op: walletOp(ton.OpcodeInternalTransfer)},
+		{AccountAddress: "0:wallet2", Deployer: "0:wallet1", Timestamp: time.Now(), Seqno: 12, Op: walletOp(ton.OpcodeInternalTransfer)},
+		{AccountAddress: "0:owner2", Deployer: "0:wallet2", Timestamp: time.Now(), Seqno: 12, Op: walletOp(ton.OpcodeTransferNotification)},
+		{AccountAddress: "0:wallet2", Deployer: "0:owner2", Timestamp: time.Now(), Seqno: 13, Op: walletOp(ton.OpcodeBurn)},
+		// Перевод от чужого кошелька не привязывается
+		{AccountAddress: "0:wallet3", Deployer: "0:foreign", Timestamp: time.Now(), Seqno: 13, Op: walletOp(ton.OpcodeInternalTransfer)},
+	}
+	for _, event := range events {
+		if err := proc.Handle(context.Background(), event); err != nil {
+			t.Fatalf("handle returned error: %v", err)
+		}
+	}
+
+	want := []struct {
+		kind   ton.JettonOpKind
+		wallet string
+	}{
+		{ton.OpInternalTransfer, "0:wallet1"},
+		{ton.OpInternalTransfer, "0:wallet2"},
+		{ton.OpTransferNotification, "0:wallet2"},
+		{ton.OpBurn, "0:wallet2"},
+	}
+	if len(notifier.ops) != len(want) {
+		t.Fatalf("expected %d jetton ops, got %d", len(want), len(notifier.ops))
+	}
+	for i, w := range want {
+		ev := notifier.ops[i]
+		if ev.Minter != "0:minter" || ev.Wallet != w.wallet || ev.Event.Op.Kind != w.kind {
+			t.Fatalf("op %d: got %s/%s/%s", i, ev.Minter, ev.Wallet, ev.Event.Op.Kind)
+		}
+	}
+	if !proc.WatchesJetton("0:wallet2") || proc.WatchesJetton("0:wallet3") {
+		t.Fatalf("unexpected wallet registry state")
+	}
+}
+
+func TestProcessorDeDustPoolLiquidityAndLP(t *testing.T) {
+	logger := zap.NewNop()
+
+	minter := address.NewAddress(0, 0, bytesOf(0x11))
+	jettonAsset := cell.BeginCell().MustStoreUInt(1, 4).MustStoreInt(0, 8).MustStoreSlice(bytesOf(0x11), 256).EndCell()
+	nativeAsset := cell.BeginCell().MustStoreUInt(0, 4).EndCell()
+
+	client := &tonClientStub{
+		stacks: map[string][][]byte{"get_jetton_data": jettonDataStack()},
+	}
+	notifier := &notifierStub{}
+	proc := NewProcessor(detector.NewDetector(client, logger), client, storage.NewMemoryCache(time.Hour), notifier, logger)
+	proc.SetFingerprinter(nil)
+
+	err := proc.Handle(context.Background(), ton.Event{AccountAddress: ton.RawAddress(minter), Timestamp: time.Now(), Seqno: 10, IsDeploy: true})
+	if err != nil {
+		t.Fatalf("handle returned error: %v", err)
+	}
+
+	// Дальше контракты отвечают как пул DeDust: TON / наш jetton
+	client.codeHash = strings.Repeat("cd", 32)
+	client.stacks = map[string][][]byte{
+		"get_assets":   {nativeAsset.ToBOC(), jettonAsset.ToBOC()},
+		"get_reserves": {big.NewInt(0).Bytes(), big.NewInt(0).Bytes()},
+	}
+	factory := "0:5f0564fb5f604783db57031ce1cf668a88d4d4d6da6de4db222b4b920d6fd800"
+	events := []ton.Event{
+		// Пул от чужого деплоера не привязывается
+		{AccountAddress: "0:fakepool", Deployer: "0:someone", Timestamp: time.Now(), Seqno: 11, IsDeploy: true},
+		{AccountAddress: "0:pool", Deployer: factory, Timestamp: time.Now(), Seqno: 11, IsDeploy: true},
+		{AccountAddress: "0:pool", Timestamp: time.Now(), Seqno: 12, Dex: &ton.DexOp{
+			Amount0: "10000000000", Amount1: "1000000000000", Reserve0: "10000000000", Reserve1: "1000000000000",
+		}},
+		{AccountAddress: "0:fakepool", Timestamp: time.Now(), Seqno: 12, Dex: &ton.DexOp{Reserve0: "1", Reserve1: "1"}},
+	}
+	for _, event := range events {
+		if err := proc.Handle(context.Background(), event); err != nil {
+			t.Fatalf("handle returned error: %v", err)
+		}
+	}
+
+	if len(notifier.pools) != 2 {
+		t.Fatalf("expected pool_created and liquidity_added, got %d events", len(notifier.pools))
+	}
+	created, added := notifier.pools[0], notifier.pools[1]
+	if created.Kind != detector.PoolCreated || created.Minter != ton.RawAddress(minter) || created.JettonIndex != 1 || created.Quote != detector.NativeTON {
+		t.Fatalf("unexpected pool_created: %+v", created)
+	}
+	// 10 TON за 1000 jetton (9 знаков) -> 0.01 TON
+	if added.Kind != detector.LiquidityAdded || !added.First || added.Price != "0.010000000000" {
+		t.Fatalf("unexpected liquidity_added: %+v", added)
+	}
+
+	// LP: пул минтит LP на кошелёк провайдера, провайдер отправляет четверть supply на нулевой адрес
+	client.stacks["get_jetton_data"] = jettonDataStack()
+	lpOps := []ton.Event{
+		{AccountAddress: "0:lpwallet", Deployer: "0:pool", Timestamp: time.Now(), Seqno: 13, Op: &ton.JettonOp{Kind: ton.OpInternalTransfer, Amount: "1000000"}},
+		{AccountAddress: "0:lpwallet", Deployer: "0:provider", Timestamp: time.Now(), Seqno: 14, Op: &ton.JettonOp{Kind: ton.OpTransfer, Amount: "250000", Destination: detector.BurnAddress}},
+	}
+	for _, event := range lpOps {
+		if err := proc.Handle(context.Background(), event); err != nil {
+			t.Fatalf("handle returned error: %v", err)
+		}
+	}
+
+	if len(notifier.ops) != 0 || len(notifier.pools) != 3 {
+		t.Fatalf("expected only lp_burned for LP ops, got %d ops and %d pool events", len(notifier.ops), len(notifier.pools))
+	}
+	if burned := notifier.pools[2]; burned.Kind != detector.LPBurned || burned.LPOwner != "0:provider" || burned.LPPercent != 25 {
+		t.Fatalf("unexpected lp_burned: %+v", burned)
+	}
+}
+
+func bytesOf(b byte) []byte {
+	return []byte(strings.Repeat(string([]byte{b}), 32))
+}
+
+func TestProcessorPipelineQueuesAndDispatches(t *testing.T) {
+	logger := zap.NewNop()
+
+	client := &tonClientStub{stacks: map[string][][]byte{"get_jetton_data": jettonDataStack()}}
+	notifier := &notifierStub{}
+	proc := NewProcessor(detector.NewDetector(client, logger), client, storage.NewMemoryCache(time.Hour), notifier, logger)
+	proc.SetFingerprinter(nil)
+	if err := proc.SetPipeline(PipelineConfig{Overflow: "drop_everything"}); err == nil {
+		t.Fatalf("unknown overflow policy must be rejected")
+	}
+	if err := proc.SetPipeline(PipelineConfig{QueueSize: 2, Workers: 1, DispatchWorkers: 1, Overflow: OverflowDropNewest}); err != nil {
+		t.Fatalf("set pipeline: %v", err)
+	}
+
+	// Воркеры ещё не запущены: третье событие не помещается в очередь
+	for _, addr := range []string{"0:q1", "0:q2", "0:q3"} {
+		if err := proc.Handle(context.Background(), ton.Event{AccountAddress: addr, Timestamp: time.Now(), IsDeploy: true}); err != nil {
+			t.Fatalf("handle returned error: %v", err)
+		}
+	}
+	if s := proc.PipelineStats()[0]; s.Depth != 2 || s.Enqueued != 2 || s.Dropped != 1 || s.MaxDepth != 2 {
+		t.Fatalf("unexpected queue stats: %+v", s)
+	}
+	var stages []string
+	for _, s := range proc.PipelineStats() {
+		stages = append(stages, s.Stage)
+	}
+	if strings.Join(stages, ",") != "dedupe,verify,enrich,dispatch" {
+		t.Fatalf("unexpected pipeline stages: %v", stages)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		proc.RunPipeline(ctx)
+		close(done)
+	}()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		stats := proc.PipelineStats()
+		drained := true
+		for _, s := range stats {
+			drained = drained && s.Processed == 2
+		}
+		if drained {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("pipeline did not drain: %+v", stats)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+	<-done
+
+	if notifier.count != 2 {
+		t.Fatalf("expected 2 dispatched notifications, got %d", notifier.count)
+	}
+}
+
+func TestPipelineDroppedDeployReleasesClaim(t *testing.T) {
+	logger := zap.NewNop()
+
+	client := &tonClientStub{stacks: map[string][][]byte{}}
+	cache := storage.NewMemoryCache(time.Hour)
+	proc := NewProcessor(detector.NewDetector(client, logger), client, cache, &notifierStub{}, logger)
+	if err := proc.SetPipeline(PipelineConfig{QueueSize: 1, Workers: 1, Overflow: OverflowDropNewest}); err != nil {
+		t.Fatalf("set pipeline: %v", err)
+	}
+
+	// Воркеры не запущены: второй занятый деплой не помещается в очередь verify
+	proc.process(context.Background(), ton.Event{AccountAddress: "0:v1", IsDeploy: true}, 0)
+	proc.process(context.Background(), ton.Event{AccountAddress: "0:v2", IsDeploy: true}, 0)
+
+	if s := proc.PipelineStats()[1]; s.Stage != "verify" || s.Enqueued != 1 || s.Dropped != 1 {
+		t.Fatalf("unexpected verify stats: %+v", s)
+	}
+	if known, _ := cache.IsMinterKnown(context.Background(), "0:v1"); !known {
+		t.Fatal("queued deploy must keep its claim")
+	}
+	if known, _ := cache.IsMinterKnown(context.Background(), "0:v2"); known {
+		t.Fatal("dropped deploy must release its claim")
+	}
+}
+
+func TestPipelineShutdownDrainsQueues(t *testing.T) {
+	logger := zap.NewNop()
+
+	client := &tonClientStub{stacks: map[string][][]byte{}}
+	cache := storage.NewMemoryCache(time.Hour)
+	proc := NewProcessor(detector.NewDetector(client, logger), client, cache, &notifierStub{}, logger)
+	if err := proc.SetPipeline(PipelineConfig{QueueSize: 4, Workers: 1}); err != nil {
+		t.Fatalf("set pipeline: %v", err)
+	}
+
+	// Воркеры не запущены: события и занятый деплой остаются в очередях до остановки
+	proc.process(context.Background(), ton.Event{AccountAddress: "0:v1", IsDeploy: true}, 0)
+	if err := proc.Handle(context.Background(), ton.Event{AccountAddress: "0:e1", IsDeploy: true}); err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	proc.RunPipeline(ctx)
+
+	for _, s := range proc.PipelineStats() {
+		// Элемент, пришедший от воркера предыдущей стадии после остановки, отбрасывается без постановки
+		if s.Depth != 0 || s.Processed+s.Dropped < s.Enqueued {
+			t.Fatalf("stage %s left unaccounted items: %+v", s.Stage, s)
+		}
+	}
+	if known, _ := cache.IsMinterKnown(context.Background(), "0:v1"); known {
+		t.Fatal("deploy drained at shutdown must release its claim")
+	}
+
+	// После остановки новые элементы не принимаются
+	if err := proc.Handle(context.Background(), ton.Event{AccountAddress: "0:e2", IsDeploy: true}); err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+	if s := proc.PipelineStats()[0]; s.Depth != 0 {
+		t.Fatalf("event accepted after shutdown: %+v", s)
+	}
+}
+
+func TestPipelineShardsKeepPerKeyOrder(t *testing.T) {
+	proc := NewProcessor(nil, nil, storage.NewMemoryCache(time.Hour), nil, zap.NewNop())
+	now := time.Now()
+	proc.minters.add("0:minter", 9, false, now)
+	proc.minters.addWallet("0:wallet", "0:minter", now)
+
+	// Операции кошелька и минтера попадают в один шард
+	walletOp := ton.Event{AccountAddress: "0:owner", Deployer: "0:wallet", Op: &ton.JettonOp{Kind: ton.OpTransferNotification}}
+	burnOp := ton.Event{AccountAddress: "0:wallet", Op: &ton.JettonOp{Kind: ton.OpBurn}}
+	for _, event := range []ton.Event{walletOp, burnOp} {
+		if key := proc.shardKey(event); key != "0:minter" {
+			t.Fatalf("op %s keyed by %q, want minter", event.Op.Kind, key)
+		}
+	}
+	if key := proc.shardKey(ton.Event{AccountAddress: "0:deploy", IsDeploy: true}); key != "0:deploy" {
+		t.Fatalf("deploy keyed by %q", key)
+	}
+
+	type item struct {
+		key string
+		seq int
+	}
+	done := make(chan struct{})
+	q := newQueue("dedupe", 64, 4, func(it item) string { return it.key }, OverflowBlock, done, zap.NewNop())
+
+	var mu sync.Mutex
+	seen := make(map[string][]int)
+	keys := []string{"a", "b", "c", "d", "e"}
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	q.run(ctx, &wg, func(it item) {
+		time.Sleep(time.Duration(it.seq%3) * time.Millisecond)
+		mu.Lock()
+		seen[it.key] = append(seen[it.key], it.seq)
+		mu.Unlock()
+	})
+
+	for seq := 0; seq < 10; seq++ {
+		for _, key := range keys {
+			q.push(context.Background(), item{key: key, seq: seq})
+		}
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for q.processed.Load() < 50 {
+		if time.Now().After(deadline) {
+			t.Fatalf("queue did not drain: %+v", q.stats())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	cancel()
+	close(done)
+	wg.Wait()
+
+	for _, key := range keys {
+		for i, seq := range seen[key] {
+			if seq != i {
+				t.Fatalf("key %s processed out of order: %v", key, seen[key])
+			}
+		}
+	}
+}
+
+func TestProcessorHandleHonoursContextWhenQueueBlocks(t *testing.T) {
+	logger := zap.NewNop()
+
+	client := &tonClientStub{stacks: map[string][][]byte{}}
+	proc := NewProcessor(detector.NewDetector(client, logger), client, storage.NewMemoryCache(time.Hour), &notifierStub{}, logger)
+	if err := proc.SetPipeline(PipelineConfig{QueueSize: 1, Workers: 1, Overflow: OverflowBlock}); err != nil {
+		t.Fatalf("set pipeline: %v", err)
+	}
+
+	if err := proc.Handle(context.Background(), ton.Event{AccountAddress: "0:b1", IsDeploy: true}); err != nil {
+		t.Fatalf("handle returned error: %v", err)
+	}
+
+	// Очередь полна и воркеры не запущены: ожидание места прерывается отменой ctx
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	started := time.Now()
+	if err := proc.Handle(ctx, ton.Event{AccountAddress: "0:b2", IsDeploy: true}); err != nil {
+		t.Fatalf("handle returned error: %v", err)
+	}
+	if elapsed := time.Since(started); elapsed > time.Second {
+		t.Fatalf("handle blocked for %s after ctx cancel", elapsed)
+	}
+	if s := proc.PipelineStats()[0]; s.Enqueued != 1 || s.Dropped != 1 {
+		t.Fatalf("unexpected queue stats: %+v", s)
+	}
+}
+
+func TestProcessorClaimDeduplicatesReplicas(t *testing.T) {
+	logger := zap.NewNop()
+
+	client := &tonClientStub{stacks: map[string][][]byte{"get_jetton_data": jettonDataStack()}}
+	first := storage.NewMemoryCache(time.Hour)
+	second := first.WithOwner("replica-2")
+
+	notifier := &notifierStub{}
+	replicas := []*Processor{
+		NewProcessor(detector.NewDetector(client, logger), client, first, notifier, logger),
+		NewProcessor(detector.NewDetector(client, logger), client, second, notifier, logger),
+	}
+
+	// Один и тот же деплой приходит в обе реплики (realtime одной и catchup другой)
+	event := ton.Event{AccountAddress: "0:shared", Timestamp: time.Now(), IsDeploy: true}
+	for _, proc := range replicas {
+		proc.SetFingerprinter(nil)
+		if err := proc.Handle(context.Background(), event); err != nil {
+			t.Fatalf("handle returned error: %v", err)
+		}
+	}
+	if notifier.count != 1 {
+		t.Fatalf("expected one notification across replicas, got %d", notifier.count)
+	}
+
+	// Чужой claim не освобождается, свой — освобождается
+	ctx := context.Background()
+	if ok, _ := second.ClaimMinter(ctx, "0:lease", time.Minute); !ok {
+		t.Fatalf("claim of a free address failed")
+	}
+	first.ReleaseMinter(ctx, "0:lease") //nolint:errcheck
+	if ok, _ := first.ClaimMinter(ctx, "0:lease", time.Minute); ok {
+		t.Fatalf("foreign claim was released")
+	}
+	second.ReleaseMinter(ctx, "0:lease") //nolint:errcheck
+	if ok, _ := first.ClaimMinter(ctx, "0:lease", time.Minute); !ok {
+		t.Fatalf("own claim was not released")
+	}
+}
@@ -0,0 +1,147 @@
+package processor
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/yourname/hyper-sniper-indexer/pkg/ton"
+	"go.uber.org/zap"
+)
+
+const (
+	// Как часто забираем наступившие задачи из очереди
+	recheckPollInterval = 500 * time.Millisecond
+
+	// Максимум задач за один опрос
+	recheckBatchSize = 50
+
+	// Сколько повторных проверок идёт параллельно без конвейера
+	recheckWorkers = 8
+
+	// Дедлайн постановки задачи: дедлайн проверки к этому моменту часто уже истёк
+	recheckScheduleTimeout = 2 * time.Second
+)
+
+// Паузы перед повторными проверками; после последней адрес отбрасывается
+var recheckBackoff = []time.Duration{time.Second, 3 * time.Second, 10 * time.Second, 60 * time.Second}
+
+// RecheckQueue хранит отложенные повторные проверки (реализуется storage.RedisCache).
+type RecheckQueue interface {
+	ScheduleRecheck(ctx context.Context, payload []byte, at time.Time) error
+	DueRechecks(ctx context.Context, now time.Time, limit int) ([][]byte, error)
+}
+
+// recheckItem — задача в очереди: исходное событие и номер следующей попытки.
+type recheckItem struct {
+	Event   ton.Event `json:"event"`
+	Attempt int       `json:"attempt"`
+	Reason  string    `json:"reason"`
+}
+
+// SetRecheckQueue включает повторные проверки адресов, не прошедших верификацию из-за временных сбоев.
+func (p *Processor) SetRecheckQueue(q RecheckQueue) {
+	p.rechecks = q
+}
+
+// scheduleRecheck ставит событие в очередь после неудачной попытки attempt.
+// Ожидание аккаунта могло исчерпать дедлайн проверки, поэтому постановка получает собственный.
+func (p *Processor) scheduleRecheck(ctx context.Context, event ton.Event, attempt int, reason error) {
+	if p.rechecks == nil {
+		return
+	}
+
+	if attempt >= len(recheckBackoff) {
+		p.logger.Warn("повторные проверки исчерпаны, адрес отброшен",
+			zap.String("address", event.AccountAddress),
+			zap.Int("attempts", attempt),
+			zap.Error(reason),
+		)
+		return
+	}
+
+	payload, err := json.Marshal(recheckItem{
+		Event:   event,
+		Attempt: attempt + 1,
+		Reason:  reason.Error(),
+	})
+	if err != nil {
+		p.logger.Warn("не удалось сериализовать задачу повторной проверки", zap.Error(err))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), recheckScheduleTimeout)
+	defer cancel()
+
+	delay := recheckBackoff[attempt]
+	if err := p.rechecks.ScheduleRecheck(ctx, payload, time.Now().Add(delay)); err != nil {
+		p.logger.Warn("не удалось поставить повторную проверку", zap.Error(err))
+		return
+	}
+
+	p.logger.Debug("адрес поставлен на повторную проверку",
+		zap.String("address", event.AccountAddress),
+		zap.Int("attempt", attempt+1),
+		zap.Duration("delay", delay),
+		zap.Error(reason),
+	)
+}
+
+// RunRechecks обрабатывает очередь повторных проверок до отмены ctx.
+// С конвейером наступившие задачи идут в его очередь verify, без конвейера — в пул из recheckWorkers воркеров.
+// Из очереди забирается не больше задач, чем свободных воркеров: забранная задача сразу начинает
+// проверку, а прерванная остановкой проверка снова ставится в очередь.
+func (p *Processor) RunRechecks(ctx context.Context) {
+	if p.rechecks == nil {
+		return
+	}
+
+	ticker := time.NewTicker(recheckPollInterval)
+	defer ticker.Stop()
+
+	slots := make(chan struct{}, recheckWorkers)
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		limit := recheckBatchSize
+		if p.pipeline == nil {
+			limit = min(limit, cap(slots)-len(slots))
+			if limit == 0 {
+				continue
+			}
+		}
+
+		payloads, err := p.rechecks.DueRechecks(ctx, time.Now(), limit)
+		if err != nil {
+			p.logger.Warn("ошибка чтения очереди повторных проверок", zap.Error(err))
+		}
+
+		for _, payload := range payloads {
+			var item recheckItem
+			if err := json.Unmarshal(payload, &item); err != nil {
+				p.logger.Warn("некорректная задача повторной проверки", zap.Error(err))
+				continue
+			}
+			if p.pipeline != nil {
+				p.process(ctx, item.Event, item.Attempt)
+				continue
+			}
+
+			slots <- struct{}{}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer func() { <-slots }()
+				p.process(ctx, item.Event, item.Attempt)
+			}()
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package processor
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/yourname/hyper-sniper-indexer/internal/detector"
+	"github.com/yourname/hyper-sniper-indexer/internal/rules"
+	"github.com/yourname/hyper-sniper-indexer/internal/trace"
+	"github.com/yourname/hyper-sniper-indexer/pkg/ton"
+	"go.uber.org/zap"
+)
+
+// matchRules проверяет деплой правилами из конфига и отправляет совпадения в их маршруты.
+// Для известного code_hash fingerprint не снимался: берём его, только если он нужен правилам.
+func (p *Processor) matchRules(ctx context.Context, event ton.Event, target detector.Target, cls *detector.Classification) {
+	started := time.Now()
+
+	interfaces := target.Interfaces
+	if cls != nil && len(cls.Interfaces) > 0 {
+		interfaces = cls.Interfaces
+	}
+	if interfaces == nil && p.rules.NeedsInterfaces() && p.prints != nil {
+		interfaces = p.prints.Fingerprint(ctx, target)
+	}
+
+	kind := rules.KindUnknown
+	if cls != nil {
+		kind = string(cls.Kind)
+	}
+	deploy := rules.Deploy{
+		Address:    event.AccountAddress,
+		CodeHash:   target.CodeHash,
+		Kind:       kind,
+		Interfaces: interfaces,
+		Workchain:  event.Workchain,
+		Deployer:   event.Deployer,
+	}
+
+	matched := p.rules.Match(deploy)
+	names := make([]string, 0, len(matched))
+	for _, r := range matched {
+		names = append(names, r.Name)
+	}
+	trace.Record(ctx, "rules", started, strings.Join(names, ","), nil)
+	if len(matched) == 0 {
+		return
+	}
+
+	p.logger.Info("📐 деплой совпал с правилами",
+		zap.String("address", deploy.Address),
+		zap.String("kind", deploy.Kind),
+		zap.String("code_hash", deploy.CodeHash),
+		zap.Strings("rules", names),
+	)
+
+	if p.notifier != nil {
+		for _, r := range matched {
+			p.notifier.NotifyRule(ctx, &rules.Hit{Rule: r, Deploy: deploy}, &event)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package rules
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/yourname/hyper-sniper-indexer/pkg/ton"
+)
+
+// KindUnknown — тип деплоя, который не распознал ни один детектор.
+const KindUnknown = "unknown"
+
+// Rule — правило из конфига: какие деплои отправлять и куда.
+// Поля условия объединяются по И, значения внутри поля — по ИЛИ;
+// Interfaces требует ответа на все перечисленные get-методы.
+type Rule struct {
+	Name       string
+	CodeHashes []string
+	Interfaces []string
+	Kinds      []string // detector.Kind или KindUnknown
+	Workchains []int32
+	Deployers  []string
+
+	// Маршрут: пустые поля — основной Telegram-чат / webhook
+	TgChatID   string
+	WebhookURL string
+}
+
+// Deploy — задеплоенный контракт, который проверяется правилами.
+type Deploy struct {
+	Address    string   `json:"address"`
+	CodeHash   string   `json:"code_hash"`
+	Kind       string   `json:"kind"`
+	Interfaces []string `json:"interfaces,omitempty"`
+	Workchain  int32    `json:"workchain"`
+	Deployer   string   `json:"deployer,omitempty"`
+}
+
+// Hit — деплой, совпавший с правилом.
+type Hit struct {
+	Rule   *Rule
+	Deploy Deploy
+}
+
+// Set — набор правил с нормализованными адресами и code_hash.
+type Set struct {
+	rules      []*Rule
+	interfaces bool
+}
+
+// New проверяет и нормализует правила. Правило без условий совпало бы с любым деплоем — это ошибка.
+func New(rules []Rule) (*Set, error) {
+	s := &Set{rules: make([]*Rule, 0, len(rules))}
+	for i := range rules {
+		r := rules[i]
+		if r.Name == "" {
+			r.Name = fmt.Sprintf("rule_%d", i+1)
+		}
+		if len(r.CodeHashes)+len(r.Interfaces)+len(r.Kinds)+len(r.Workchains)+len(r.Deployers) == 0 {
+			return nil, fmt.Errorf("правило %q: нет ни одного условия", r.Name)
+		}
+
+		hashes := make([]string, 0, len(r.CodeHashes))
+		for _, h := range r.CodeHashes {
+			h = strings.ToLower(strings.TrimSpace(h))
+			if b, err := hex.DecodeString(h); err != nil || len(b) != 32 {
+				return nil, fmt.Errorf("правило %q: некорректный code_hash: %q", r.Name, h)
+			}
+			hashes = append(hashes, h)
+		}
+		r.CodeHashes = hashes
+
+		deployers := make([]string, 0, len(r.Deployers))
+		for _, d := range r.Deployers {
+			addr, err := ton.ParseAddress(strings.TrimSpace(d))
+			if err != nil {
+				return nil, fmt.Errorf("правило %q: некорректный адрес деплоера: %q", r.Name, d)
+			}
+			deployers = append(deployers, ton.RawAddress(addr))
+		}
+		r.Deployers = deployers
+
+		if len(r.Interfaces) > 0 {
+			s.interfaces = true
+		}
+		s.rules = append(s.rules, &r)
+	}
+	return s, nil
+}
+
+// Len возвращает число правил.
+func (s *Set) Len() int {
+	return len(s.rules)
+}
+
+// NeedsInterfaces сообщает, что хотя бы одному правилу нужен fingerprint интерфейсов.
+func (s *Set) NeedsInterfaces() bool {
+	return s.interfaces
+}
+
+// Match возвращает все правила, с которыми совпал деплой.
+func (s *Set) Match(d Deploy) []*Rule {
+	var matched []*Rule
+	for _, r := range s.rules {
+		if r.match(d) {
+			matched = append(matched, r)
+		}
+	}
+	return matched
+}
+
+func (r *Rule) match(d Deploy) bool {
+	if len(r.CodeHashes) > 0 && !contains(r.CodeHashes, strings.ToLower(d.CodeHash)) {
+		return false
+	}
+	if len(r.Kinds) > 0 && !contains(r.Kinds, d.Kind) {
+		return false
+	}
+	if len(r.Deployers) > 0 && !contains(r.Deployers, d.Deployer) {
+		return false
+	}
+	if len(r.Workchains) > 0 {
+		found := false
+		for _, wc := range r.Workchains {
+			if wc == d.Workchain {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	for _, method := range r.Interfaces {
+		if !contains(d.Interfaces, method) {
+			return false
+		}
+	}
+	return true
+}
+
+func contains(list []string, v string) bool {
+	for _, item := range list {
+		if item == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,66 @@
+package storage
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// confirmScript продлевает claim до полного TTL (0 — без срока), если он наш или уже истёк.
+var confirmScript = redis.NewScript(`
+local v = redis.call('GET', KEYS[1])
+if v == false or v == ARGV[1] then
+	if tonumber(ARGV[2]) > 0 then
+		redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[2])
+	else
+		redis.call('SET', KEYS[1], ARGV[1])
+	end
+	return 1
+end
+return 0
+`)
+
+// releaseScript удаляет claim, только если он наш.
+var releaseScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// instanceID возвращает владельца claim: hostname, pid и случайный суффикс на случай одинаковых контейнеров.
+func instanceID() string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "indexer"
+	}
+	suffix := make([]byte, 4)
+	rand.Read(suffix) //nolint:errcheck
+	return fmt.Sprintf("%s-%d-%s", host, os.Getpid(), hex.EncodeToString(suffix))
+}
+
+// Owner возвращает владельца claim этого инстанса.
+func (c *RedisCache) Owner() string {
+	return c.owner
+}
+
+// ClaimMinter атомарно занимает адрес (SET NX со значением-владельцем и TTL аренды).
+// false — адрес уже обработан или его проверяет другой воркер или инстанс.
+func (c *RedisCache) ClaimMinter(ctx context.Context, address string, lease time.Duration) (bool, error) {
+	return c.client.SetNX(ctx, c.minterKey(address), c.owner, lease).Result()
+}
+
+// ConfirmMinter помечает занятый адрес обработанным: claim продлевается до TTL кэша минтеров.
+func (c *RedisCache) ConfirmMinter(ctx context.Context, address string) error {
+	return confirmScript.Run(ctx, c.client, []string{c.minterKey(address)}, c.owner, c.minterTTL.Milliseconds()).Err()
+}
+
+// ReleaseMinter освобождает claim, чтобы адрес можно было проверить повторно.
+func (c *RedisCache) ReleaseMinter(ctx context.Context, address string) error {
+	return releaseScript.Run(ctx, c.client, []string{c.minterKey(address)}, c.owner).Err()
+}
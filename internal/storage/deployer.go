@@ -0,0 +1,39 @@
+package storage
+
+import (
+	"context"
+	"time"
+)
+
+// История деплоера: LIST JSON-записей, новые в начале.
+const (
+	deployerKeyPrefix  = "hsi:deployer:"
+	deployerHistoryLen = 200
+	deployerHistoryTTL = 30 * 24 * time.Hour
+)
+
+// AppendDeployment добавляет запись в историю деплоера и обрезает её до deployerHistoryLen.
+func (c *RedisCache) AppendDeployment(ctx context.Context, deployer string, payload []byte) error {
+	key := deployerKeyPrefix + deployer
+
+	pipe := c.client.TxPipeline()
+	pipe.LPush(ctx, key, payload)
+	pipe.LTrim(ctx, key, 0, deployerHistoryLen-1)
+	pipe.Expire(ctx, key, deployerHistoryTTL)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// Deployments возвращает историю деплоера от новых записей к старым.
+func (c *RedisCache) Deployments(ctx context.Context, deployer string) ([][]byte, error) {
+	members, err := c.client.LRange(ctx, deployerKeyPrefix+deployer, 0, deployerHistoryLen-1).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([][]byte, 0, len(members))
+	for _, m := range members {
+		out = append(out, []byte(m))
+	}
+	return out, nil
+}
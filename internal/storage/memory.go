@@ -0,0 +1,123 @@
+package storage
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// memoryStore — общее состояние MemoryCache; копии WithOwner изображают реплики над одним Redis.
+type memoryStore struct {
+	mu     sync.Mutex
+	claims map[string]memoryClaim
+	seqnos map[uint32]bool
+}
+
+type memoryClaim struct {
+	owner   string
+	expires time.Time // нулевое — без срока
+}
+
+// MemoryCache — in-memory эквивалент антидублей RedisCache для тестов и запуска без Redis.
+type MemoryCache struct {
+	store     *memoryStore
+	owner     string
+	minterTTL time.Duration
+}
+
+// NewMemoryCache создаёт кэш с TTL обработанных минтеров.
+func NewMemoryCache(ttl time.Duration) *MemoryCache {
+	return &MemoryCache{
+		store: &memoryStore{
+			claims: make(map[string]memoryClaim),
+			seqnos: make(map[uint32]bool),
+		},
+		owner:     instanceID(),
+		minterTTL: ttl,
+	}
+}
+
+// WithOwner возвращает кэш над тем же состоянием с другим владельцем claim (вторая реплика).
+func (c *MemoryCache) WithOwner(owner string) *MemoryCache {
+	return &MemoryCache{store: c.store, owner: owner, minterTTL: c.minterTTL}
+}
+
+// RegisterSeqno сохраняет seqno и возвращает true, если он новый.
+func (c *MemoryCache) RegisterSeqno(_ context.Context, seqno uint32) (bool, error) {
+	if seqno == 0 {
+		return true, nil
+	}
+	c.store.mu.Lock()
+	defer c.store.mu.Unlock()
+
+	if c.store.seqnos[seqno] {
+		return false, nil
+	}
+	c.store.seqnos[seqno] = true
+	return true, nil
+}
+
+// IsMinterKnown проверяет, занят ли адрес.
+func (c *MemoryCache) IsMinterKnown(_ context.Context, address string) (bool, error) {
+	c.store.mu.Lock()
+	defer c.store.mu.Unlock()
+
+	_, ok := c.store.live(strings.ToLower(address), time.Now())
+	return ok, nil
+}
+
+// ClaimMinter атомарно занимает адрес на время аренды.
+func (c *MemoryCache) ClaimMinter(_ context.Context, address string, lease time.Duration) (bool, error) {
+	c.store.mu.Lock()
+	defer c.store.mu.Unlock()
+
+	key, now := strings.ToLower(address), time.Now()
+	if _, ok := c.store.live(key, now); ok {
+		return false, nil
+	}
+	c.store.claims[key] = memoryClaim{owner: c.owner, expires: now.Add(lease)}
+	return true, nil
+}
+
+// ConfirmMinter продлевает свой (или истёкший) claim до TTL минтеров.
+func (c *MemoryCache) ConfirmMinter(_ context.Context, address string) error {
+	c.store.mu.Lock()
+	defer c.store.mu.Unlock()
+
+	key, now := strings.ToLower(address), time.Now()
+	if claim, ok := c.store.live(key, now); ok && claim.owner != c.owner {
+		return nil
+	}
+	claim := memoryClaim{owner: c.owner}
+	if c.minterTTL > 0 {
+		claim.expires = now.Add(c.minterTTL)
+	}
+	c.store.claims[key] = claim
+	return nil
+}
+
+// ReleaseMinter удаляет свой claim.
+func (c *MemoryCache) ReleaseMinter(_ context.Context, address string) error {
+	c.store.mu.Lock()
+	defer c.store.mu.Unlock()
+
+	key := strings.ToLower(address)
+	if claim, ok := c.store.live(key, time.Now()); ok && claim.owner == c.owner {
+		delete(c.store.claims, key)
+	}
+	return nil
+}
+
+// live возвращает неистёкший claim. Вызывается под mu.
+func (s *memoryStore) live(key string, now time.Time) (memoryClaim, bool) {
+	claim, ok := s.claims[key]
+	if !ok {
+		return memoryClaim{}, false
+	}
+	if !claim.expires.IsZero() && !now.Before(claim.expires) {
+		delete(s.claims, key)
+		return memoryClaim{}, false
+	}
+	return claim, true
+}
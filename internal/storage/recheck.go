@@ -0,0 +1,46 @@
+package storage
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Очередь повторных проверок: ZSET, score — время запуска в unix ms.
+const recheckQueueKey = "hsi:recheck"
+
+// ScheduleRecheck ставит задачу повторной проверки на момент at.
+func (c *RedisCache) ScheduleRecheck(ctx context.Context, payload []byte, at time.Time) error {
+	return c.client.ZAdd(ctx, recheckQueueKey, redis.Z{
+		Score:  float64(at.UnixMilli()),
+		Member: payload,
+	}).Err()
+}
+
+// DueRechecks забирает из очереди до limit задач, время которых наступило.
+// Задача достаётся тому, чей ZREM её удалил, поэтому несколько инстансов не дублируют проверки.
+func (c *RedisCache) DueRechecks(ctx context.Context, now time.Time, limit int) ([][]byte, error) {
+	members, err := c.client.ZRangeByScore(ctx, recheckQueueKey, &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   strconv.FormatInt(now.UnixMilli(), 10),
+		Count: int64(limit),
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	due := make([][]byte, 0, len(members))
+	for _, member := range members {
+		removed, err := c.client.ZRem(ctx, recheckQueueKey, member).Result()
+		if err != nil {
+			return due, err
+		}
+		if removed > 0 {
+			due = append(due, []byte(member))
+		}
+	}
+
+	return due, nil
+}
@@ -20,6 +20,7 @@ type RedisCache struct {
 	client      *redis.Client
 	seqnoWindow int64
 	minterTTL   time.Duration
+	owner       string // владелец claim минтеров: этот инстанс
 }
 
 // NewRedisCache создаёт клиента Redis.
@@ -45,6 +46,7 @@ func NewRedisCache(addr string, ttl time.Duration, seqnoWindow int) (*RedisCache
 		client:      client,
 		seqnoWindow: win,
 		minterTTL:   ttl,
+		owner:       instanceID(),
 	}, nil
 }
 
@@ -88,12 +90,6 @@ func (c *RedisCache) IsMinterKnown(ctx context.Context, address string) (bool, e
 	return exists > 0, nil
 }
 
-// RememberMinter помечает адрес как обработанный.
-func (c *RedisCache) RememberMinter(ctx context.Context, address string) error {
-	key := c.minterKey(address)
-	return c.client.Set(ctx, key, 1, c.minterTTL).Err()
-}
-
 func (c *RedisCache) trimSeqno(ctx context.Context) error {
 	count, err := c.client.ZCard(ctx, seqnoSetKey).Result()
 	if err != nil {
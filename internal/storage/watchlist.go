@@ -0,0 +1,31 @@
+package storage
+
+import "context"
+
+// Список наблюдения: HASH, поле — "kind:value", значение — JSON записи.
+const watchlistKey = "hsi:watchlist"
+
+// SaveWatch сохраняет запись списка наблюдения.
+func (c *RedisCache) SaveWatch(ctx context.Context, key string, payload []byte) error {
+	return c.client.HSet(ctx, watchlistKey, key, payload).Err()
+}
+
+// DeleteWatch удаляет запись и возвращает true, если она была.
+func (c *RedisCache) DeleteWatch(ctx context.Context, key string) (bool, error) {
+	removed, err := c.client.HDel(ctx, watchlistKey, key).Result()
+	return removed > 0, err
+}
+
+// LoadWatches возвращает все записи списка наблюдения.
+func (c *RedisCache) LoadWatches(ctx context.Context) ([][]byte, error) {
+	values, err := c.client.HVals(ctx, watchlistKey).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([][]byte, 0, len(values))
+	for _, v := range values {
+		out = append(out, []byte(v))
+	}
+	return out, nil
+}
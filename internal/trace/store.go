@@ -0,0 +1,97 @@
+package trace
+
+import "sync"
+
+const defaultCapacity = 10000
+
+// Store хранит последние решения в кольцевом буфере фиксированного размера
+// с индексом по адресу. Старые решения вытесняются новыми.
+type Store struct {
+	mu        sync.RWMutex
+	ring      []*Decision
+	next      int
+	size      int
+	byAddress map[string][]int // адрес -> позиции в ring, от старых к новым
+}
+
+// NewStore создаёт хранилище на capacity решений (0 — значение по умолчанию).
+func NewStore(capacity int) *Store {
+	if capacity <= 0 {
+		capacity = defaultCapacity
+	}
+	return &Store{
+		ring:      make([]*Decision, capacity),
+		byAddress: make(map[string][]int),
+	}
+}
+
+// Add сохраняет решение, вытесняя самое старое при заполнении буфера.
+func (s *Store) Add(d *Decision) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if old := s.ring[s.next]; old != nil {
+		s.unindex(old.Address, s.next)
+	}
+
+	s.ring[s.next] = d
+	s.byAddress[d.Address] = append(s.byAddress[d.Address], s.next)
+
+	s.next = (s.next + 1) % len(s.ring)
+	if s.size < len(s.ring) {
+		s.size++
+	}
+}
+
+// ByAddress возвращает решения по адресу, новые первыми.
+func (s *Store) ByAddress(address string) []Decision {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	positions := s.byAddress[address]
+	out := make([]Decision, 0, len(positions))
+	for i := len(positions) - 1; i >= 0; i-- {
+		out = append(out, *s.ring[positions[i]])
+	}
+	return out
+}
+
+// Recent возвращает до limit последних решений, новые первыми.
+func (s *Store) Recent(limit int) []Decision {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if limit <= 0 || limit > s.size {
+		limit = s.size
+	}
+
+	out := make([]Decision, 0, limit)
+	for i := 1; i <= limit; i++ {
+		pos := (s.next - i + len(s.ring)) % len(s.ring)
+		out = append(out, *s.ring[pos])
+	}
+	return out
+}
+
+// Len возвращает число сохранённых решений.
+func (s *Store) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.size
+}
+
+// unindex убирает позицию вытесняемого решения из индекса. Вызывается под s.mu.
+func (s *Store) unindex(address string, pos int) {
+	positions := s.byAddress[address]
+	for i, p := range positions {
+		if p == pos {
+			positions = append(positions[:i], positions[i+1:]...)
+			break
+		}
+	}
+	if len(positions) == 0 {
+		delete(s.byAddress, address)
+		return
+	}
+	s.byAddress[address] = positions
+}
@@ -0,0 +1,26 @@
+package trace
+
+import "testing"
+
+func TestStoreEvictsOldest(t *testing.T) {
+	s := NewStore(3)
+	for _, addr := range []string{"0:a", "0:b", "0:a", "0:c", "0:d"} {
+		s.Add(&Decision{Address: addr})
+	}
+
+	if s.Len() != 3 {
+		t.Fatalf("expected 3 decisions, got %d", s.Len())
+	}
+	// Первое решение по 0:a и решение по 0:b вытеснены
+	if got := s.ByAddress("0:a"); len(got) != 1 {
+		t.Fatalf("expected one decision for 0:a, got %d", len(got))
+	}
+	if got := s.ByAddress("0:b"); len(got) != 0 {
+		t.Fatalf("evicted decision still indexed: %+v", got)
+	}
+
+	recent := s.Recent(2)
+	if len(recent) != 2 || recent[0].Address != "0:d" || recent[1].Address != "0:c" {
+		t.Fatalf("unexpected recent order: %+v", recent)
+	}
+}
@@ -0,0 +1,122 @@
+package trace
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Verdict — итог обработки деплоя.
+type Verdict string
+
+const (
+	VerdictAccepted   Verdict = "accepted"   // контракт распознан и отправлен
+	VerdictRejected   Verdict = "rejected"   // ни один детектор не подошёл
+	VerdictSuppressed Verdict = "suppressed" // распознан, но уведомление подавлено (блок-лист)
+	VerdictDuplicate  Verdict = "duplicate"  // адрес уже обработан
+	VerdictDeferred   Verdict = "deferred"   // временный сбой, деплой в очереди повторов
+	VerdictError      Verdict = "error"      // проверка не выполнена
+)
+
+// Step — один шаг проверки: кэш, code_hash, get-метод, парсинг.
+type Step struct {
+	Name       string `json:"name"`
+	Result     string `json:"result"`
+	Error      string `json:"error,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+// Decision — запись о том, почему деплой принят или отклонён.
+type Decision struct {
+	Address    string    `json:"address"`
+	CodeHash   string    `json:"code_hash,omitempty"`
+	Seqno      uint32    `json:"seqno"`
+	TxHash     string    `json:"tx_hash,omitempty"`
+	Attempt    int       `json:"attempt"`
+	StartedAt  time.Time `json:"started_at"`
+	Verdict    Verdict   `json:"verdict"`
+	Reason     string    `json:"reason"`
+	Kind       string    `json:"kind,omitempty"`
+	Steps      []Step    `json:"steps"`
+	DurationMs int64     `json:"duration_ms"`
+}
+
+// Recorder собирает шаги одного решения. Безопасен для параллельных шагов.
+type Recorder struct {
+	mu       sync.Mutex
+	decision Decision
+}
+
+// Begin начинает запись решения по деплою.
+func Begin(address string, seqno uint32, txHash string, attempt int) *Recorder {
+	return &Recorder{decision: Decision{
+		Address:   address,
+		Seqno:     seqno,
+		TxHash:    txHash,
+		Attempt:   attempt,
+		StartedAt: time.Now().UTC(),
+	}}
+}
+
+// Step добавляет шаг, начатый в started. err может быть nil.
+func (r *Recorder) Step(name string, started time.Time, result string, err error) {
+	step := Step{
+		Name:       name,
+		Result:     result,
+		DurationMs: time.Since(started).Milliseconds(),
+	}
+	if err != nil {
+		step.Error = err.Error()
+	}
+
+	r.mu.Lock()
+	r.decision.Steps = append(r.decision.Steps, step)
+	r.mu.Unlock()
+}
+
+// SetCodeHash запоминает code_hash, как только он известен.
+func (r *Recorder) SetCodeHash(codeHash string) {
+	r.mu.Lock()
+	r.decision.CodeHash = codeHash
+	r.mu.Unlock()
+}
+
+// Decide фиксирует вердикт. Повторный вызов перезаписывает предыдущий.
+func (r *Recorder) Decide(verdict Verdict, kind, reason string) {
+	r.mu.Lock()
+	r.decision.Verdict = verdict
+	r.decision.Kind = kind
+	r.decision.Reason = reason
+	r.mu.Unlock()
+}
+
+// Finish возвращает готовое решение с общим временем обработки.
+func (r *Recorder) Finish() *Decision {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	d := r.decision
+	d.Steps = append([]Step(nil), r.decision.Steps...)
+	d.DurationMs = time.Since(d.StartedAt).Milliseconds()
+	return &d
+}
+
+type recorderKey struct{}
+
+// WithRecorder кладёт recorder в ctx, чтобы детекторы могли записывать шаги.
+func WithRecorder(ctx context.Context, r *Recorder) context.Context {
+	return context.WithValue(ctx, recorderKey{}, r)
+}
+
+// FromContext возвращает recorder из ctx (nil, если трассировка выключена).
+func FromContext(ctx context.Context) *Recorder {
+	r, _ := ctx.Value(recorderKey{}).(*Recorder)
+	return r
+}
+
+// Record добавляет шаг в решение из ctx. Без recorder ничего не делает.
+func Record(ctx context.Context, name string, started time.Time, result string, err error) {
+	if r := FromContext(ctx); r != nil {
+		r.Step(name, started, result, err)
+	}
+}
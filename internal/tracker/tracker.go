@@ -0,0 +1,286 @@
+package tracker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/yourname/hyper-sniper-indexer/internal/detector"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultInterval  = 30 * time.Second
+	defaultWindow    = 24 * time.Hour
+	defaultMaxTokens = 5000
+
+	// Сколько минтеров перечитываем параллельно
+	checkWorkers = 8
+)
+
+// JettonReader перечитывает get_jetton_data (реализуется detector.Detector).
+type JettonReader interface {
+	ReadJettonData(ctx context.Context, addr string) (*detector.JettonData, error)
+}
+
+// Notifier доставляет изменения (реализуется notifier.Notifier).
+type Notifier interface {
+	NotifyChange(ctx context.Context, change *detector.Change)
+}
+
+// snapshot — последнее известное состояние минтера.
+type snapshot struct {
+	address     string
+	name        string
+	symbol      string
+	totalSupply string
+	mintable    bool
+	admin       string
+	contentURI  string
+	contentHash string
+	detectedAt  time.Time
+}
+
+// Tracker периодически перечитывает get_jetton_data у недавно найденных минтеров
+// и сообщает об изменениях supply, mintable, админа и content.
+type Tracker struct {
+	reader    JettonReader
+	notifier  Notifier
+	interval  time.Duration
+	window    time.Duration
+	maxTokens int
+	logger    *zap.Logger
+
+	mu     sync.Mutex
+	tokens map[string]*snapshot
+}
+
+// New создаёт трекер. Нулевые interval/window/maxTokens заменяются значениями по умолчанию.
+func New(reader JettonReader, ntf Notifier, interval, window time.Duration, maxTokens int, logger *zap.Logger) *Tracker {
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+	if window <= 0 {
+		window = defaultWindow
+	}
+	if maxTokens <= 0 {
+		maxTokens = defaultMaxTokens
+	}
+
+	return &Tracker{
+		reader:    reader,
+		notifier:  ntf,
+		interval:  interval,
+		window:    window,
+		maxTokens: maxTokens,
+		logger:    logger,
+		tokens:    make(map[string]*snapshot),
+	}
+}
+
+// Track ставит найденный минтер на наблюдение.
+// При переполнении вытесняется самый старый минтер.
+func (t *Tracker) Track(meta *detector.Metadata) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, ok := t.tokens[meta.Address]; ok {
+		return
+	}
+	if len(t.tokens) >= t.maxTokens {
+		t.evictOldest()
+	}
+
+	detectedAt := meta.Timestamp
+	if detectedAt.IsZero() {
+		detectedAt = time.Now().UTC()
+	}
+
+	t.tokens[meta.Address] = &snapshot{
+		address:     meta.Address,
+		name:        meta.Name,
+		symbol:      meta.Symbol,
+		totalSupply: meta.TotalSupply,
+		mintable:    meta.Mintable,
+		admin:       meta.AdminAddr,
+		contentURI:  meta.ContentURI,
+		contentHash: meta.ContentHash,
+		detectedAt:  detectedAt,
+	}
+}
+
+// Len возвращает число минтеров под наблюдением.
+func (t *Tracker) Len() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.tokens)
+}
+
+// Run перечитывает минтеры каждые interval до отмены ctx.
+func (t *Tracker) Run(ctx context.Context) {
+	t.logger.Info("трекер минтеров запущен",
+		zap.Duration("interval", t.interval),
+		zap.Duration("window", t.window),
+	)
+
+	ticker := time.NewTicker(t.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			t.CheckAll(ctx)
+		}
+	}
+}
+
+// CheckAll выполняет один проход по всем минтерам в окне наблюдения.
+func (t *Tracker) CheckAll(ctx context.Context) {
+	tokens := t.active(time.Now())
+
+	sem := make(chan struct{}, checkWorkers)
+	var wg sync.WaitGroup
+	for _, snap := range tokens {
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return
+		case sem <- struct{}{}:
+		}
+
+		wg.Add(1)
+		go func(snap snapshot) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			t.check(ctx, snap)
+		}(snap)
+	}
+	wg.Wait()
+}
+
+// active возвращает копии снапшотов в окне наблюдения и удаляет устаревшие.
+func (t *Tracker) active(now time.Time) []snapshot {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	tokens := make([]snapshot, 0, len(t.tokens))
+	for addr, snap := range t.tokens {
+		if now.Sub(snap.detectedAt) > t.window {
+			delete(t.tokens, addr)
+			continue
+		}
+		tokens = append(tokens, *snap)
+	}
+	return tokens
+}
+
+// check перечитывает один минтер и отправляет найденные изменения.
+func (t *Tracker) check(ctx context.Context, prev snapshot) {
+	data, err := t.reader.ReadJettonData(ctx, prev.address)
+	if err != nil {
+		if !errors.Is(err, detector.ErrTransient) {
+			t.logger.Debug("минтер не ответил на get_jetton_data",
+				zap.String("address", prev.address),
+				zap.Error(err),
+			)
+		}
+		return
+	}
+
+	changes := diff(prev, data, time.Now().UTC())
+
+	t.mu.Lock()
+	if snap, ok := t.tokens[prev.address]; ok {
+		snap.totalSupply = data.TotalSupply
+		snap.mintable = data.Mintable
+		snap.admin = data.AdminAddr
+		snap.contentURI = data.ContentURI
+		snap.contentHash = data.ContentHash
+		if data.Name != "" {
+			snap.name = data.Name
+		}
+		if data.Symbol != "" {
+			snap.symbol = data.Symbol
+		}
+	}
+	t.mu.Unlock()
+
+	for _, change := range changes {
+		t.logger.Info("изменение минтера",
+			zap.String("kind", string(change.Kind)),
+			zap.String("address", change.Address),
+			zap.String("old", change.Old),
+			zap.String("new", change.New),
+			zap.Duration("since_launch", change.SinceLaunch()),
+		)
+		if t.notifier != nil {
+			t.notifier.NotifyChange(ctx, change)
+		}
+	}
+}
+
+// diff сравнивает сохранённое состояние с новым ответом get_jetton_data.
+func diff(prev snapshot, data *detector.JettonData, now time.Time) []*detector.Change {
+	var changes []*detector.Change
+	add := func(kind detector.ChangeKind, oldValue, newValue string) {
+		changes = append(changes, &detector.Change{
+			Kind:       kind,
+			Address:    prev.address,
+			Name:       prev.name,
+			Symbol:     prev.symbol,
+			Old:        oldValue,
+			New:        newValue,
+			DetectedAt: prev.detectedAt,
+			ChangedAt:  now,
+		})
+	}
+
+	if data.TotalSupply != prev.totalSupply {
+		add(detector.ChangeSupply, prev.totalSupply, data.TotalSupply)
+	}
+
+	if data.Mintable != prev.mintable {
+		add(detector.ChangeMintable, boolString(prev.mintable), boolString(data.Mintable))
+	}
+
+	switch {
+	case prev.admin != "" && data.AdminAddr == "":
+		add(detector.ChangeAdminRenounced, prev.admin, "")
+	case data.AdminAddr != prev.admin:
+		add(detector.ChangeAdminChanged, prev.admin, data.AdminAddr)
+	}
+
+	// Хэш ячейки content ловит и on-chain метаданные; URI — если хэша нет
+	contentChanged := data.ContentURI != prev.contentURI
+	if prev.contentHash != "" && data.ContentHash != prev.contentHash {
+		contentChanged = true
+	}
+	if contentChanged {
+		add(detector.ChangeContent, prev.contentURI, data.ContentURI)
+	}
+
+	return changes
+}
+
+// evictOldest удаляет самый давно найденный минтер. Вызывается под t.mu.
+func (t *Tracker) evictOldest() {
+	var oldest *snapshot
+	for _, snap := range t.tokens {
+		if oldest == nil || snap.detectedAt.Before(oldest.detectedAt) {
+			oldest = snap
+		}
+	}
+	if oldest != nil {
+		delete(t.tokens, oldest.address)
+	}
+}
+
+func boolString(v bool) string {
+	if v {
+		return "true"
+	}
+	return "false"
+}
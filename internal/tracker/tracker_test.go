@@ -0,0 +1,82 @@
+package tracker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/yourname/hyper-sniper-indexer/internal/detector"
+	"go.uber.org/zap"
+)
+
+type readerStub struct {
+	data *detector.JettonData
+}
+
+func (r *readerStub) ReadJettonData(context.Context, string) (*detector.JettonData, error) {
+	return r.data, nil
+}
+
+type notifierStub struct {
+	changes []*detector.Change
+}
+
+func (n *notifierStub) NotifyChange(_ context.Context, change *detector.Change) {
+	n.changes = append(n.changes, change)
+}
+
+func TestTrackerEmitsAdminRenounced(t *testing.T) {
+	reader := &readerStub{data: &detector.JettonData{
+		TotalSupply: "1000",
+		Mintable:    true,
+		AdminAddr:   "EQadmin",
+		ContentURI:  "https://example.com/a.json",
+	}}
+	ntf := &notifierStub{}
+	trk := New(reader, ntf, time.Second, time.Hour, 0, zap.NewNop())
+
+	trk.Track(&detector.Metadata{
+		Address:     "0:minter",
+		Symbol:      "TST",
+		TotalSupply: "1000",
+		Mintable:    true,
+		AdminAddr:   "EQadmin",
+		ContentURI:  "https://example.com/a.json",
+		Timestamp:   time.Now().Add(-10 * time.Minute),
+	})
+
+	trk.CheckAll(context.Background())
+	if len(ntf.changes) != 0 {
+		t.Fatalf("unchanged minter produced events: %+v", ntf.changes)
+	}
+
+	// Админ отказался от прав и выключил минт
+	reader.data = &detector.JettonData{TotalSupply: "1000", ContentURI: "https://example.com/a.json"}
+	trk.CheckAll(context.Background())
+
+	kinds := map[detector.ChangeKind]bool{}
+	for _, change := range ntf.changes {
+		kinds[change.Kind] = true
+	}
+	if len(ntf.changes) != 2 || !kinds[detector.ChangeAdminRenounced] || !kinds[detector.ChangeMintable] {
+		t.Fatalf("unexpected changes: %+v", ntf.changes)
+	}
+	if since := ntf.changes[0].SinceLaunch(); since < 10*time.Minute {
+		t.Fatalf("unexpected since launch: %s", since)
+	}
+
+	// Повторный проход по новому состоянию ничего не шлёт
+	trk.CheckAll(context.Background())
+	if len(ntf.changes) != 2 {
+		t.Fatalf("state was not updated: %+v", ntf.changes)
+	}
+}
+
+func TestDiffSupplyAndContent(t *testing.T) {
+	prev := snapshot{address: "0:m", totalSupply: "1", contentHash: "aa", contentURI: "u"}
+	changes := diff(prev, &detector.JettonData{TotalSupply: "2", ContentHash: "bb", ContentURI: "u"}, time.Now())
+
+	if len(changes) != 2 || changes[0].Kind != detector.ChangeSupply || changes[1].Kind != detector.ChangeContent {
+		t.Fatalf("unexpected changes: %+v", changes)
+	}
+}
@@ -0,0 +1,324 @@
+package watchlist
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yourname/hyper-sniper-indexer/pkg/ton"
+	"go.uber.org/zap"
+)
+
+// Kind — тип записи списка наблюдения.
+type Kind string
+
+const (
+	KindAddress  Kind = "address"   // деплоер, админ или любой кошелёк
+	KindCodeHash Kind = "code_hash" // код контракта
+)
+
+// Источник записи.
+const (
+	SourceConfig = "config"
+	SourceAPI    = "api"
+)
+
+// Поле события, в котором найдено совпадение.
+const (
+	FieldAccount  = "account"   // транзакция самого наблюдаемого аккаунта
+	FieldSender   = "sender"    // наблюдаемый адрес отправил входящее сообщение
+	FieldDeployer = "deployer"  // наблюдаемый адрес задеплоил контракт
+	FieldCodeHash = "code_hash" // задеплоен наблюдаемый код
+	FieldAdmin    = "admin"     // наблюдаемый адрес — админ нового минтера
+)
+
+const defaultReloadInterval = 30 * time.Second
+
+// ErrNotFound возвращается при удалении отсутствующей записи.
+var ErrNotFound = errors.New("записи нет в списке наблюдения")
+
+// Entry — адрес или code_hash под наблюдением.
+type Entry struct {
+	Kind    Kind      `json:"kind"`
+	Value   string    `json:"value"` // raw-адрес или code_hash в нижнем регистре
+	Label   string    `json:"label,omitempty"`
+	Source  string    `json:"source"`
+	AddedAt time.Time `json:"added_at"`
+}
+
+// Match — совпадение события с записью списка.
+type Match struct {
+	Entry
+	Field string `json:"field"` // Field*
+}
+
+// Store хранит записи, добавленные через API (реализуется storage.RedisCache).
+type Store interface {
+	SaveWatch(ctx context.Context, key string, payload []byte) error
+	DeleteWatch(ctx context.Context, key string) (bool, error)
+	LoadWatches(ctx context.Context) ([][]byte, error)
+}
+
+// Watchlist — список наблюдения: записи из конфига плюс записи из Redis.
+// Проверки идут по копии в памяти, Redis периодически перечитывается (записи с других инстансов).
+type Watchlist struct {
+	store  Store
+	logger *zap.Logger
+
+	mu      sync.RWMutex
+	config  map[string]Entry // key -> запись из конфига
+	dynamic map[string]Entry // key -> запись из Redis/API
+}
+
+// New создаёт пустой список наблюдения. store может быть nil (только конфиг).
+func New(store Store, logger *zap.Logger) *Watchlist {
+	return &Watchlist{
+		store:   store,
+		logger:  logger,
+		config:  make(map[string]Entry),
+		dynamic: make(map[string]Entry),
+	}
+}
+
+// AddConfig добавляет запись из конфига (в Redis не сохраняется).
+func (w *Watchlist) AddConfig(kind Kind, value, label string) error {
+	entry, err := NewEntry(kind, value, label, SourceConfig)
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	w.config[entry.key()] = entry
+	w.mu.Unlock()
+	return nil
+}
+
+// Add добавляет запись и сохраняет её в Redis.
+func (w *Watchlist) Add(ctx context.Context, entry Entry) error {
+	if w.store != nil {
+		payload, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		if err := w.store.SaveWatch(ctx, entry.key(), payload); err != nil {
+			return fmt.Errorf("не удалось сохранить запись: %w", err)
+		}
+	}
+
+	w.mu.Lock()
+	w.dynamic[entry.key()] = entry
+	w.mu.Unlock()
+	return nil
+}
+
+// Remove удаляет запись, добавленную через API. Записи из конфига не удаляются.
+func (w *Watchlist) Remove(ctx context.Context, kind Kind, value string) error {
+	value, err := normalize(kind, value)
+	if err != nil {
+		return err
+	}
+	key := string(kind) + ":" + value
+
+	w.mu.Lock()
+	_, ok := w.dynamic[key]
+	delete(w.dynamic, key)
+	w.mu.Unlock()
+
+	if w.store != nil {
+		removed, err := w.store.DeleteWatch(ctx, key)
+		if err != nil {
+			return err
+		}
+		ok = ok || removed
+	}
+	if !ok {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// List возвращает все записи, отсортированные по типу и значению.
+func (w *Watchlist) List() []Entry {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	out := make([]Entry, 0, len(w.config)+len(w.dynamic))
+	for _, entry := range w.config {
+		out = append(out, entry)
+	}
+	for key, entry := range w.dynamic {
+		if _, ok := w.config[key]; !ok {
+			out = append(out, entry)
+		}
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Kind != out[j].Kind {
+			return out[i].Kind < out[j].Kind
+		}
+		return out[i].Value < out[j].Value
+	})
+	return out
+}
+
+// WatchesAddress сообщает, наблюдается ли raw-адрес (ton.TxFilter: вызывается на каждую транзакцию).
+func (w *Watchlist) WatchesAddress(addr string) bool {
+	if addr == "" {
+		return false
+	}
+	key := string(KindAddress) + ":" + addr
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	_, ok := w.config[key]
+	if !ok {
+		_, ok = w.dynamic[key]
+	}
+	return ok
+}
+
+// MatchEvent ищет совпадения события: аккаунт и отправитель для транзакций,
+// деплоер и code_hash для деплоев.
+func (w *Watchlist) MatchEvent(event ton.Event) []Match {
+	var matches []Match
+	if event.IsDeploy {
+		matches = append(matches, w.MatchAddress(FieldDeployer, event.Deployer)...)
+		matches = append(matches, w.match(KindCodeHash, FieldCodeHash, strings.ToLower(event.CodeHash))...)
+		return matches
+	}
+
+	matches = append(matches, w.MatchAddress(FieldAccount, event.AccountAddress)...)
+	matches = append(matches, w.MatchAddress(FieldSender, event.Deployer)...)
+	return matches
+}
+
+// MatchAddress проверяет адрес в поле field (например, админ найденного минтера).
+func (w *Watchlist) MatchAddress(field, addr string) []Match {
+	return w.match(KindAddress, field, addr)
+}
+
+func (w *Watchlist) match(kind Kind, field, value string) []Match {
+	if value == "" {
+		return nil
+	}
+	if kind == KindAddress {
+		parsed, err := ton.ParseAddress(value)
+		if err != nil {
+			return nil
+		}
+		value = ton.RawAddress(parsed)
+	}
+	key := string(kind) + ":" + value
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if entry, ok := w.config[key]; ok {
+		return []Match{{Entry: entry, Field: field}}
+	}
+	if entry, ok := w.dynamic[key]; ok {
+		return []Match{{Entry: entry, Field: field}}
+	}
+	return nil
+}
+
+// Run перечитывает записи из Redis каждые interval до отмены ctx.
+func (w *Watchlist) Run(ctx context.Context, interval time.Duration) {
+	if w.store == nil {
+		return
+	}
+	if interval <= 0 {
+		interval = defaultReloadInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := w.Reload(ctx); err != nil {
+				w.logger.Warn("не удалось перечитать список наблюдения", zap.Error(err))
+			}
+		}
+	}
+}
+
+// Reload заменяет записи из Redis актуальным содержимым хранилища.
+func (w *Watchlist) Reload(ctx context.Context) error {
+	if w.store == nil {
+		return nil
+	}
+
+	payloads, err := w.store.LoadWatches(ctx)
+	if err != nil {
+		return err
+	}
+
+	dynamic := make(map[string]Entry, len(payloads))
+	for _, payload := range payloads {
+		var entry Entry
+		if err := json.Unmarshal(payload, &entry); err != nil {
+			continue
+		}
+		dynamic[entry.key()] = entry
+	}
+
+	w.mu.Lock()
+	w.dynamic = dynamic
+	w.mu.Unlock()
+	return nil
+}
+
+// NewEntry проверяет и нормализует запись: адрес приводится к raw-виду, code_hash — к нижнему регистру.
+func NewEntry(kind Kind, value, label, source string) (Entry, error) {
+	value, err := normalize(kind, value)
+	if err != nil {
+		return Entry{}, err
+	}
+	return Entry{
+		Kind:    kind,
+		Value:   value,
+		Label:   label,
+		Source:  source,
+		AddedAt: time.Now().UTC(),
+	}, nil
+}
+
+func normalize(kind Kind, value string) (string, error) {
+	value = strings.TrimSpace(value)
+	switch kind {
+	case KindAddress:
+		addr, err := ton.ParseAddress(value)
+		if err != nil {
+			return "", fmt.Errorf("некорректный адрес: %q", value)
+		}
+		return ton.RawAddress(addr), nil
+	case KindCodeHash:
+		value = strings.ToLower(value)
+		if b, err := hex.DecodeString(value); err != nil || len(b) != 32 {
+			return "", fmt.Errorf("некорректный code_hash: %q", value)
+		}
+		return value, nil
+	}
+	return "", fmt.Errorf("неизвестный тип записи: %q", kind)
+}
+
+func (e Entry) key() string {
+	return string(e.Kind) + ":" + e.Value
+}
+
+// Hit — событие, совпавшее со списком наблюдения (уходит отдельным маршрутом с высоким приоритетом).
+type Hit struct {
+	Address      string // аккаунт транзакции или задеплоенный контракт
+	IsDeploy     bool
+	ContractKind string // тип контракта, если уже известен (совпадение по админу)
+	Matches      []Match
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
 	"math/big"
 	"net/http"
 	"runtime"
@@ -16,6 +18,7 @@ import (
 	"github.com/xssnick/tonutils-go/liteclient"
 	"github.com/xssnick/tonutils-go/tlb"
 	"github.com/xssnick/tonutils-go/ton"
+	"github.com/xssnick/tonutils-go/tvm/cell"
 	"go.uber.org/zap"
 )
 
@@ -27,14 +30,53 @@ const (
 	// Интервал опроса новых блоков
 	blockPollInterval = 100 * time.Millisecond
 
-	// Таймаут на получение блока
+	// Таймаут на получение блока и на каждый запрос транзакций шарда.
+	// Обработчик событий получает ctx подписки: его стадии задают собственные дедлайны
 	blockTimeout = 5 * time.Second
 
 	// Количество воркеров = GOMAXPROCS * 4
 	workerMultiplier = 4
+
+	// Код выхода liteserver'а для get-метода на неинициализированном аккаунте
+	exitCodeAccountNotInit = -256
 )
 
-// Event описывает данные о транзакции с деплоем.
+// ErrNotReady означает, что блок или аккаунт ещё не виден на liteserver'е.
+// Запрос имеет смысл повторить позже или на следующем блоке.
+var ErrNotReady = errors.New("состояние ещё недоступно")
+
+// IsExitCode сообщает, что get-метод завершился кодом выхода контракта (в том числе «метода нет»).
+// Это ответ самого контракта, а не сбой сети или liteserver'а, и повторять его бессмысленно.
+func IsExitCode(err error) bool {
+	var execErr ton.ContractExecError
+	return errors.As(err, &execErr)
+}
+
+// BlockRef ссылается на блок мастерчейна, на состоянии которого выполняются запросы.
+type BlockRef struct {
+	Seqno uint32
+}
+
+type blockRefKey struct{}
+
+// WithBlock привязывает запросы RunGetMethod/GetCodeHash с этим контекстом к блоку ref.
+// Так детекторы, работающие через обычные методы, читают состояние на блоке деплоя.
+func WithBlock(ctx context.Context, ref BlockRef) context.Context {
+	return context.WithValue(ctx, blockRefKey{}, ref)
+}
+
+// LatestBlock снимает привязку WithBlock: запросы с таким ctx идут на последний блок.
+func LatestBlock(ctx context.Context) context.Context {
+	return WithBlock(ctx, BlockRef{})
+}
+
+// BlockFromContext возвращает блок, заданный через WithBlock.
+func BlockFromContext(ctx context.Context) (BlockRef, bool) {
+	ref, ok := ctx.Value(blockRefKey{}).(BlockRef)
+	return ref, ok
+}
+
+// Event описывает транзакцию с деплоем или, если задан TxFilter, транзакцию наблюдаемого адреса.
 type Event struct {
 	AccountAddress string
 	CodeHash       string
@@ -46,10 +88,19 @@ type Event struct {
 	TxLT           uint64
 	IsDeploy       bool
 	BlockUnixtime  int64
+	Deployer       string    // отправитель входящего сообщения: деплоер для деплоя (пусто для external)
+	Code           []byte    // BOC кода из StateInit
+	Data           []byte    // BOC данных из StateInit (может отсутствовать)
+	Op             *JettonOp // операция jetton из тела входящего сообщения (nil — нет)
+	Dex            *DexOp    // добавление ликвидности в пул (nil — нет)
 }
 
-// Handler получает события из индексатора.
-type Handler func(event Event) error
+// Handler получает события из индексатора. ctx отменяется вместе с подпиской или catchup.
+type Handler func(ctx context.Context, event Event) error
+
+// TxFilter отбирает транзакции без деплоя по raw-адресу аккаунта или отправителя.
+// Вызывается на каждую транзакцию, поэтому должен быть быстрым.
+type TxFilter func(addr string) bool
 
 // Client определяет контракт для работы с TON.
 type Client interface {
@@ -58,6 +109,8 @@ type Client interface {
 	Catchup(ctx context.Context, since time.Time, handler Handler) error
 	RunGetMethod(ctx context.Context, address string, method string, stack ...any) ([][]byte, error)
 	GetCodeHash(ctx context.Context, address string) (string, error)
+	RunGetMethodAt(ctx context.Context, ref BlockRef, address string, method string, stack ...any) ([][]byte, error)
+	GetCodeHashAt(ctx context.Context, ref BlockRef, address string) (string, error)
 }
 
 // LatencyStats хранит статистику по задержкам.
@@ -80,6 +133,7 @@ type IndexerClient struct {
 	mu           sync.RWMutex
 	lastMC       uint32
 	shardWorkers int
+	txFilter     TxFilter
 
 	stats        LatencyStats
 	blocksTotal  int64
@@ -108,6 +162,12 @@ func NewIndexerClient(network string, liteservers []string, logger *zap.Logger)
 	}
 }
 
+// SetTxFilter включает события для транзакций без деплоя (IsDeploy=false),
+// если аккаунт или отправитель проходят фильтр. Вызывается до Subscribe/Catchup.
+func (c *IndexerClient) SetTxFilter(f TxFilter) {
+	c.txFilter = f
+}
+
 // Start подключается к liteserver'ам.
 func (c *IndexerClient) Start(ctx context.Context) error {
 	c.pool = liteclient.NewConnectionPool()
@@ -248,14 +308,12 @@ func (c *IndexerClient) processBlock(ctx context.Context, seqno uint32, handler
 		go func() {
 			defer wg.Done()
 			for shard := range shardChan {
-				shardCtx, shardCancel := context.WithTimeout(ctx, blockTimeout)
-				if err := c.processShard(shardCtx, shard, seqno, handler); err != nil {
+				if err := c.processShard(ctx, shard, seqno, handler); err != nil {
 					c.logger.Debug("ошибка обработки шарда",
 						zap.Int32("workchain", shard.Workchain),
 						zap.Error(err),
 					)
 				}
-				shardCancel()
 			}
 		}()
 	}
@@ -271,15 +329,18 @@ func (c *IndexerClient) processBlock(ctx context.Context, seqno uint32, handler
 	return nil
 }
 
-// processShard обрабатывает транзакции одного шарда.
+// processShard обрабатывает транзакции одного шарда. Запросы к liteserver'у ограничены blockTimeout,
+// обработчик получает ctx вызывающего и не делит этот бюджет с остальными транзакциями шарда.
 func (c *IndexerClient) processShard(ctx context.Context, shard *ton.BlockIDExt, mcSeqno uint32, handler Handler) error {
 	// Получаем все транзакции шарда
 	var fetchedIDs []ton.TransactionShortInfo
 	var after *ton.TransactionID3
 	var more = true
 
+	listCtx, cancel := context.WithTimeout(ctx, blockTimeout)
+	defer cancel()
 	for more {
-		ids, hasMore, err := c.api.GetBlockTransactionsV2(ctx, shard, 100, after)
+		ids, hasMore, err := c.api.GetBlockTransactionsV2(listCtx, shard, 100, after)
 		if err != nil {
 			return fmt.Errorf("ошибка получения транзакций: %w", err)
 		}
@@ -304,7 +365,9 @@ func (c *IndexerClient) processShard(ctx context.Context, shard *ton.BlockIDExt,
 	// Обрабатываем каждую транзакцию
 	for _, txInfo := range fetchedIDs {
 		// Получаем полную транзакцию для анализа
-		txList, err := c.api.GetTransaction(ctx, shard, address.NewAddress(0, byte(shard.Workchain), txInfo.Account), txInfo.LT)
+		txCtx, txCancel := context.WithTimeout(ctx, blockTimeout)
+		txList, err := c.api.GetTransaction(txCtx, shard, address.NewAddress(0, byte(shard.Workchain), txInfo.Account), txInfo.LT)
+		txCancel()
 		if err != nil {
 			c.logger.Debug("не удалось получить транзакцию", zap.Error(err))
 			continue
@@ -315,31 +378,57 @@ func (c *IndexerClient) processShard(ctx context.Context, shard *ton.BlockIDExt,
 		}
 
 		// Проверяем, является ли это деплоем
-		isDeploy, codeHash := c.analyzeTransaction(txList)
-		if !isDeploy {
+		stateInit, deployer := c.analyzeTransaction(txList)
+		addrStr := RawAddress(address.NewAddress(0, byte(shard.Workchain), txInfo.Account))
+		if stateInit == nil {
+			// Транзакции без деплоя нужны только для наблюдаемых адресов
+			if c.txFilter == nil || !(c.txFilter(addrStr) || c.txFilter(deployer)) {
+				continue
+			}
+			event := Event{
+				AccountAddress: addrStr,
+				Timestamp:      time.Unix(int64(txList.Now), 0),
+				Seqno:          mcSeqno,
+				Workchain:      shard.Workchain,
+				Shard:          shard.Shard,
+				TxHash:         hex.EncodeToString(txList.Hash),
+				TxLT:           txInfo.LT,
+				BlockUnixtime:  blockUnixtime,
+				Deployer:       deployer,
+				Op:             parseOp(txList),
+				Dex:            parseDexOp(txList),
+			}
+			if err := handler(ctx, event); err != nil {
+				c.logger.Warn("ошибка обработчика события", zap.Error(err))
+			}
 			continue
 		}
 
 		atomic.AddInt64(&c.deploysTotal, 1)
 
-		addrStr := fmt.Sprintf("%d:%s", shard.Workchain, hex.EncodeToString(txInfo.Account))
-
 		event := Event{
 			AccountAddress: addrStr,
-			CodeHash:       codeHash,
+			CodeHash:       hex.EncodeToString(stateInit.Code.Hash()),
 			Timestamp:      time.Unix(int64(txList.Now), 0),
 			Seqno:          mcSeqno,
 			Workchain:      shard.Workchain,
 			Shard:          shard.Shard,
+			TxHash:         hex.EncodeToString(txList.Hash),
 			TxLT:           txInfo.LT,
 			IsDeploy:       true,
 			BlockUnixtime:  blockUnixtime,
+			Deployer:       deployer,
+			Code:           stateInit.Code.ToBOC(),
+			Op:             parseOp(txList),
+		}
+		if stateInit.Data != nil {
+			event.Data = stateInit.Data.ToBOC()
 		}
 
 		latencyMs := time.Now().UnixMilli() - (int64(txList.Now) * 1000)
 		c.recordLatency(latencyMs)
 
-		if err := handler(event); err != nil {
+		if err := handler(ctx, event); err != nil {
 			c.logger.Warn("ошибка обработчика события", zap.Error(err))
 		}
 	}
@@ -348,46 +437,45 @@ func (c *IndexerClient) processShard(ctx context.Context, shard *ton.BlockIDExt,
 }
 
 // analyzeTransaction проверяет, является ли транзакция деплоем.
-func (c *IndexerClient) analyzeTransaction(tx *tlb.Transaction) (isDeploy bool, codeHash string) {
+// Возвращает StateInit с кодом (nil, если это не деплой) и, для internal-сообщения,
+// адрес отправителя (для деплоя — деплоера).
+func (c *IndexerClient) analyzeTransaction(tx *tlb.Transaction) (*tlb.StateInit, string) {
 	if tx == nil {
-		return false, ""
+		return nil, ""
 	}
 
 	// Проверяем входящее сообщение
 	if tx.IO.In == nil {
-		return false, ""
+		return nil, ""
 	}
 
 	inMsg := tx.IO.In.Msg
 	if inMsg == nil {
-		return false, ""
+		return nil, ""
 	}
 
 	// Проверяем наличие StateInit (признак деплоя)
 	var stateInit *tlb.StateInit
+	var deployer string
 
 	switch m := inMsg.(type) {
 	case *tlb.InternalMessage:
 		stateInit = m.StateInit
+		if m.SrcAddr != nil && !m.SrcAddr.IsAddrNone() {
+			deployer = RawAddress(m.SrcAddr)
+		}
 	case *tlb.ExternalMessage:
 		stateInit = m.StateInit
 	default:
-		return false, ""
-	}
-
-	if stateInit == nil {
-		return false, ""
+		return nil, ""
 	}
 
-	// Есть StateInit — это деплой!
-	if stateInit.Code == nil {
-		return false, ""
+	// Есть StateInit с кодом — это деплой!
+	if stateInit == nil || stateInit.Code == nil {
+		return nil, deployer
 	}
 
-	codeHashBytes := stateInit.Code.Hash()
-	codeHash = hex.EncodeToString(codeHashBytes)
-
-	return true, codeHash
+	return stateInit, deployer
 }
 
 // recordLatency записывает latency для статистики.
@@ -480,27 +568,41 @@ func (c *IndexerClient) Catchup(ctx context.Context, since time.Time, handler Ha
 	return nil
 }
 
-// RunGetMethod вызывает get-метод контракта.
+// RunGetMethod вызывает get-метод контракта на последнем блоке мастерчейна
+// (или на блоке из контекста, см. WithBlock).
+// Числа возвращаются как big-endian байты модуля, cell и slice — как BOC.
 func (c *IndexerClient) RunGetMethod(ctx context.Context, addrStr string, method string, args ...any) ([][]byte, error) {
+	ref, _ := BlockFromContext(ctx)
+	return c.runGetMethod(ctx, ref, addrStr, method, args...)
+}
+
+// RunGetMethodAt вызывает get-метод на состоянии блока мастерчейна ref.
+// Если блок или аккаунт ещё не виден, возвращает ошибку, обёрнутую в ErrNotReady.
+func (c *IndexerClient) RunGetMethodAt(ctx context.Context, ref BlockRef, addrStr string, method string, args ...any) ([][]byte, error) {
+	return c.runGetMethod(ctx, ref, addrStr, method, args...)
+}
+
+func (c *IndexerClient) runGetMethod(ctx context.Context, ref BlockRef, addrStr string, method string, args ...any) ([][]byte, error) {
 	if c.api == nil {
 		return nil, fmt.Errorf("API клиент не инициализирован")
 	}
 
-	addr, err := address.ParseAddr(addrStr)
+	addr, err := ParseAddress(addrStr)
 	if err != nil {
-		addr, err = parseRawAddress(addrStr)
-		if err != nil {
-			return nil, fmt.Errorf("некорректный адрес %s: %w", addrStr, err)
-		}
+		return nil, fmt.Errorf("некорректный адрес %s: %w", addrStr, err)
 	}
 
-	master, err := c.api.CurrentMasterchainInfo(ctx)
+	master, err := c.masterBlock(ctx, ref)
 	if err != nil {
-		return nil, fmt.Errorf("не удалось получить мастерчейн: %w", err)
+		return nil, err
 	}
 
 	res, err := c.api.RunGetMethod(ctx, master, addr, method, args...)
 	if err != nil {
+		var execErr ton.ContractExecError
+		if errors.As(err, &execErr) && execErr.Code == exitCodeAccountNotInit {
+			return nil, fmt.Errorf("аккаунт %s не инициализирован: %w", addrStr, ErrNotReady)
+		}
 		return nil, fmt.Errorf("ошибка вызова %s: %w", method, err)
 	}
 
@@ -514,6 +616,14 @@ func (c *IndexerClient) RunGetMethod(ctx context.Context, addrStr string, method
 			result = append(result, v.Bytes())
 		case string:
 			result = append(result, []byte(v))
+		case *cell.Cell:
+			result = append(result, v.ToBOC())
+		case *cell.Slice:
+			c, err := v.ToCell()
+			if err != nil {
+				return nil, fmt.Errorf("некорректный slice в ответе %s: %w", method, err)
+			}
+			result = append(result, c.ToBOC())
 		default:
 			result = append(result, []byte(fmt.Sprintf("%v", v)))
 		}
@@ -522,23 +632,31 @@ func (c *IndexerClient) RunGetMethod(ctx context.Context, addrStr string, method
 	return result, nil
 }
 
-// GetCodeHash возвращает code_hash аккаунта.
+// GetCodeHash возвращает code_hash аккаунта на последнем блоке (или на блоке из контекста).
+// Неактивный аккаунт даёт ошибку, обёрнутую в ErrNotReady.
 func (c *IndexerClient) GetCodeHash(ctx context.Context, addrStr string) (string, error) {
+	ref, _ := BlockFromContext(ctx)
+	return c.getCodeHash(ctx, ref, addrStr)
+}
+
+// GetCodeHashAt возвращает code_hash аккаунта на блоке мастерчейна ref.
+func (c *IndexerClient) GetCodeHashAt(ctx context.Context, ref BlockRef, addrStr string) (string, error) {
+	return c.getCodeHash(ctx, ref, addrStr)
+}
+
+func (c *IndexerClient) getCodeHash(ctx context.Context, ref BlockRef, addrStr string) (string, error) {
 	if c.api == nil {
 		return "", fmt.Errorf("API клиент не инициализирован")
 	}
 
-	addr, err := address.ParseAddr(addrStr)
+	addr, err := ParseAddress(addrStr)
 	if err != nil {
-		addr, err = parseRawAddress(addrStr)
-		if err != nil {
-			return "", fmt.Errorf("некорректный адрес: %w", err)
-		}
+		return "", fmt.Errorf("некорректный адрес: %w", err)
 	}
 
-	master, err := c.api.CurrentMasterchainInfo(ctx)
+	master, err := c.masterBlock(ctx, ref)
 	if err != nil {
-		return "", fmt.Errorf("не удалось получить мастерчейн: %w", err)
+		return "", err
 	}
 
 	acc, err := c.api.GetAccount(ctx, master, addr)
@@ -547,7 +665,7 @@ func (c *IndexerClient) GetCodeHash(ctx context.Context, addrStr string) (string
 	}
 
 	if !acc.IsActive || acc.State == nil {
-		return "", fmt.Errorf("аккаунт не активен")
+		return "", fmt.Errorf("аккаунт %s не активен: %w", addrStr, ErrNotReady)
 	}
 
 	if acc.Code == nil {
@@ -558,6 +676,41 @@ func (c *IndexerClient) GetCodeHash(ctx context.Context, addrStr string) (string
 	return hex.EncodeToString(codeHash), nil
 }
 
+// masterBlock возвращает блок мастерчейна для запроса: ref.Seqno == 0 — последний.
+// Для конкретного seqno ждёт, пока liteserver получит блок (в пределах дедлайна ctx).
+func (c *IndexerClient) masterBlock(ctx context.Context, ref BlockRef) (*ton.BlockIDExt, error) {
+	if ref.Seqno == 0 {
+		master, err := c.api.CurrentMasterchainInfo(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("не удалось получить мастерчейн: %w", err)
+		}
+		return master, nil
+	}
+
+	master, err := c.api.WaitForBlock(ref.Seqno).LookupBlock(ctx, address.MasterchainID, math.MinInt64, ref.Seqno)
+	if err != nil {
+		if errors.Is(err, ton.ErrBlockNotFound) || ctx.Err() != nil {
+			return nil, fmt.Errorf("блок %d: %w", ref.Seqno, ErrNotReady)
+		}
+		return nil, fmt.Errorf("не удалось найти блок %d: %w", ref.Seqno, err)
+	}
+	return master, nil
+}
+
+// ParseAddress парсит адрес в user-friendly или raw формате ("workchain:hex").
+func ParseAddress(s string) (*address.Address, error) {
+	addr, err := address.ParseAddr(s)
+	if err == nil {
+		return addr, nil
+	}
+	return parseRawAddress(s)
+}
+
+// RawAddress форматирует адрес в raw-виде "workchain:hex", как в Event.
+func RawAddress(addr *address.Address) string {
+	return fmt.Sprintf("%d:%s", addr.Workchain(), hex.EncodeToString(addr.Data()))
+}
+
 // parseRawAddress парсит адрес в формате "workchain:hex".
 func parseRawAddress(raw string) (*address.Address, error) {
 	var workchain int32
@@ -0,0 +1,116 @@
+package ton
+
+import (
+	"github.com/xssnick/tonutils-go/tlb"
+	"github.com/xssnick/tonutils-go/tvm/cell"
+)
+
+// Опкоды добавления ликвидности в пулы DEX.
+const (
+	// STON.fi v1: lp_account -> pool, когда внесены обе стороны пары
+	OpcodeStonfiCbAddLiquidity uint32 = 0x56dfeb8a
+	// DeDust: external-out событие пула deposit
+	OpcodeDeDustDeposit uint32 = 0xb544f4a4
+)
+
+// DexOp — добавление ликвидности в пул. Суммы в минимальных единицах в порядке токенов пула.
+type DexOp struct {
+	Opcode   uint32
+	Provider string // кто внёс ликвидность
+	Amount0  string
+	Amount1  string
+	Reserve0 string // резервы после депозита (только DeDust; для STON.fi читаются get_pool_data)
+	Reserve1 string
+	Aborted  bool
+}
+
+// parseDexOp ищет добавление ликвидности в транзакции пула: входящий cb_add_liquidity
+// STON.fi или external-out событие deposit DeDust.
+func parseDexOp(tx *tlb.Transaction) *DexOp {
+	op := parseStonfiLiquidity(inboundBody(tx))
+	if op == nil {
+		op = parseDeDustDeposit(tx)
+	}
+	if op != nil {
+		op.Aborted = txAborted(tx)
+	}
+	return op
+}
+
+// parseStonfiLiquidity разбирает cb_add_liquidity query_id tot_am0 tot_am1 user_address min_lp_out.
+func parseStonfiLiquidity(body *cell.Cell) *DexOp {
+	if body == nil {
+		return nil
+	}
+
+	s := body.BeginParse()
+	opcode, err := s.LoadUInt(32)
+	if err != nil || uint32(opcode) != OpcodeStonfiCbAddLiquidity {
+		return nil
+	}
+	if _, err := s.LoadUInt(64); err != nil {
+		return nil
+	}
+	amount0, err := s.LoadBigCoins()
+	if err != nil {
+		return nil
+	}
+	amount1, err := s.LoadBigCoins()
+	if err != nil {
+		return nil
+	}
+
+	op := &DexOp{Opcode: OpcodeStonfiCbAddLiquidity, Amount0: amount0.String(), Amount1: amount1.String()}
+	if user, err := s.LoadAddr(); err == nil {
+		op.Provider = addrString(user)
+	}
+	return op
+}
+
+// parseDeDustDeposit разбирает событие deposit sender amount0 amount1 reserve0 reserve1 liquidity.
+func parseDeDustDeposit(tx *tlb.Transaction) *DexOp {
+	if tx == nil || tx.IO.Out == nil {
+		return nil
+	}
+	msgs, err := tx.IO.Out.ToSlice()
+	if err != nil {
+		return nil
+	}
+
+	for _, msg := range msgs {
+		if msg.MsgType != tlb.MsgTypeExternalOut {
+			continue
+		}
+		body := msg.AsExternalOut().Payload()
+		if body == nil {
+			continue
+		}
+
+		s := body.BeginParse()
+		opcode, err := s.LoadUInt(32)
+		if err != nil || uint32(opcode) != OpcodeDeDustDeposit {
+			continue
+		}
+		sender, err := s.LoadAddr()
+		if err != nil {
+			return nil
+		}
+		values := make([]string, 4)
+		for i := range values {
+			v, err := s.LoadBigCoins()
+			if err != nil {
+				return nil
+			}
+			values[i] = v.String()
+		}
+		return &DexOp{
+			Opcode:   OpcodeDeDustDeposit,
+			Provider: addrString(sender),
+			Amount0:  values[0],
+			Amount1:  values[1],
+			Reserve0: values[2],
+			Reserve1: values[3],
+		}
+	}
+	return nil
+}
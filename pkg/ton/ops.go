@@ -0,0 +1,276 @@
+package ton
+
+import (
+	"github.com/xssnick/tonutils-go/address"
+	"github.com/xssnick/tonutils-go/tlb"
+	"github.com/xssnick/tonutils-go/tvm/cell"
+)
+
+// Опкоды сообщений минтера и кошелька TEP-74 (reference minter и stablecoin/governed).
+const (
+	OpcodeMint             uint32 = 21 // mint#15 в reference-минтере
+	OpcodeMintGoverned     uint32 = 0x642b7d07
+	OpcodeChangeAdmin      uint32 = 3
+	OpcodeChangeAdminGov   uint32 = 0x6501f354
+	OpcodeClaimAdmin       uint32 = 0xfb88e119
+	OpcodeChangeContent    uint32 = 4
+	OpcodeChangeMetadata   uint32 = 0xcb862902
+	OpcodeBurnNotification uint32 = 0x7bdd97de
+	OpcodeInternalTransfer uint32 = 0x178d4519
+
+	OpcodeTransfer             uint32 = 0x0f8a7ea5
+	OpcodeTransferNotification uint32 = 0x7362d09c
+	OpcodeBurn                 uint32 = 0x595f07bc
+)
+
+// JettonOpKind — тип операции jetton.
+type JettonOpKind string
+
+const (
+	OpMint             JettonOpKind = "mint"
+	OpChangeAdmin      JettonOpKind = "change_admin"
+	OpClaimAdmin       JettonOpKind = "claim_admin"
+	OpChangeContent    JettonOpKind = "change_content"
+	OpBurnNotification JettonOpKind = "burn_notification"
+
+	// Операции кошелька
+	OpTransfer             JettonOpKind = "transfer"
+	OpInternalTransfer     JettonOpKind = "internal_transfer"
+	OpTransferNotification JettonOpKind = "transfer_notification"
+	OpBurn                 JettonOpKind = "burn"
+)
+
+// IsWalletOp сообщает, что операция приходит на jetton-кошелёк или от него владельцу.
+func (k JettonOpKind) IsWalletOp() bool {
+	return k == OpTransfer || k == OpInternalTransfer || k == OpTransferNotification || k == OpBurn
+}
+
+// JettonOp — разобранное входящее сообщение jetton-контракта.
+type JettonOp struct {
+	Kind    JettonOpKind
+	Opcode  uint32
+	QueryID uint64
+
+	Destination     string // mint, transfer: получатель jetton (владелец); change_admin: новый админ
+	Amount          string // mint / transfer / burn: количество jetton (минимальные единицы)
+	From            string // отправитель jetton (internal_transfer, transfer_notification) или владелец сжёгшего кошелька
+	ResponseAddress string
+	Content         []byte // change_content: BOC нового content
+	ContentURI      string // change_metadata_url (stablecoin): новый URL метаданных
+
+	Aborted bool // транзакция откатилась: операция не применена
+}
+
+// JettonEvent — операция недавно найденного jetton, привязанная к его минтеру.
+type JettonEvent struct {
+	Minter  string // адрес минтера
+	Wallet  string // кошелёк, на котором прошла операция (пусто для операций минтера)
+	Initial bool   // первый mint после деплоя
+	Event   Event  // исходная транзакция; операция — Event.Op
+}
+
+// ParseMinterOp разбирает тело входящего сообщения минтера.
+// Возвращает nil, если это не операция минтера или тело не разбирается.
+func ParseMinterOp(body *cell.Cell) *JettonOp {
+	if body == nil {
+		return nil
+	}
+
+	s := body.BeginParse()
+	opcode, err := s.LoadUInt(32)
+	if err != nil {
+		return nil
+	}
+	queryID, err := s.LoadUInt(64)
+	if err != nil {
+		return nil
+	}
+
+	op := &JettonOp{Opcode: uint32(opcode), QueryID: queryID}
+	switch op.Opcode {
+	case OpcodeMint, OpcodeMintGoverned:
+		// mint query_id to_address ton_amount master_msg:^(internal_transfer query_id jetton_amount from response ...)
+		op.Kind = OpMint
+		to, err := s.LoadAddr()
+		if err != nil {
+			return nil
+		}
+		if _, err := s.LoadBigCoins(); err != nil {
+			return nil
+		}
+		master, err := s.LoadRef()
+		if err != nil {
+			return nil
+		}
+		if code, err := master.LoadUInt(32); err != nil || uint32(code) != OpcodeInternalTransfer {
+			return nil
+		}
+		if _, err := master.LoadUInt(64); err != nil {
+			return nil
+		}
+		amount, err := master.LoadBigCoins()
+		if err != nil {
+			return nil
+		}
+		op.Destination = addrString(to)
+		op.Amount = amount.String()
+		if from, err := master.LoadAddr(); err == nil {
+			op.From = addrString(from)
+			if resp, err := master.LoadAddr(); err == nil {
+				op.ResponseAddress = addrString(resp)
+			}
+		}
+
+	case OpcodeChangeAdmin, OpcodeChangeAdminGov:
+		op.Kind = OpChangeAdmin
+		admin, err := s.LoadAddr()
+		if err != nil {
+			return nil
+		}
+		op.Destination = addrString(admin)
+
+	case OpcodeClaimAdmin:
+		op.Kind = OpClaimAdmin
+
+	case OpcodeChangeContent:
+		op.Kind = OpChangeContent
+		content, err := s.LoadRefCell()
+		if err != nil {
+			return nil
+		}
+		op.Content = content.ToBOC()
+
+	case OpcodeChangeMetadata:
+		op.Kind = OpChangeContent
+		uri, err := s.LoadStringSnake()
+		if err != nil {
+			return nil
+		}
+		op.ContentURI = uri
+
+	case OpcodeBurnNotification:
+		// burn_notification query_id amount sender response_destination
+		op.Kind = OpBurnNotification
+		amount, err := s.LoadBigCoins()
+		if err != nil {
+			return nil
+		}
+		op.Amount = amount.String()
+		if from, err := s.LoadAddr(); err == nil {
+			op.From = addrString(from)
+			if resp, err := s.LoadAddr(); err == nil {
+				op.ResponseAddress = addrString(resp)
+			}
+		}
+
+	default:
+		return nil
+	}
+
+	return op
+}
+
+// ParseWalletOp разбирает тело входящего сообщения jetton-кошелька
+// (transfer, internal_transfer, burn) или уведомления владельцу (transfer_notification).
+// Возвращает nil, если это не операция кошелька или тело не разбирается.
+func ParseWalletOp(body *cell.Cell) *JettonOp {
+	if body == nil {
+		return nil
+	}
+
+	s := body.BeginParse()
+	opcode, err := s.LoadUInt(32)
+	if err != nil {
+		return nil
+	}
+	queryID, err := s.LoadUInt(64)
+	if err != nil {
+		return nil
+	}
+
+	op := &JettonOp{Opcode: uint32(opcode), QueryID: queryID}
+	switch op.Opcode {
+	case OpcodeTransfer:
+		// transfer query_id amount destination response_destination custom_payload forward_ton_amount forward_payload
+		op.Kind = OpTransfer
+	case OpcodeInternalTransfer:
+		// internal_transfer query_id amount from response_address forward_ton_amount forward_payload
+		op.Kind = OpInternalTransfer
+	case OpcodeTransferNotification:
+		// transfer_notification query_id amount sender forward_payload
+		op.Kind = OpTransferNotification
+	case OpcodeBurn:
+		// burn query_id amount response_destination custom_payload
+		op.Kind = OpBurn
+	default:
+		return nil
+	}
+
+	amount, err := s.LoadBigCoins()
+	if err != nil {
+		return nil
+	}
+	op.Amount = amount.String()
+
+	addr, err := s.LoadAddr()
+	if err != nil {
+		return op
+	}
+	switch op.Kind {
+	case OpBurn:
+		op.ResponseAddress = addrString(addr)
+		return op
+	case OpTransfer:
+		op.Destination = addrString(addr)
+	default:
+		op.From = addrString(addr)
+	}
+	if op.Kind != OpTransferNotification {
+		if resp, err := s.LoadAddr(); err == nil {
+			op.ResponseAddress = addrString(resp)
+		}
+	}
+	return op
+}
+
+// ParseJettonOp разбирает тело входящего сообщения как операцию минтера или кошелька.
+func ParseJettonOp(body *cell.Cell) *JettonOp {
+	if op := ParseMinterOp(body); op != nil {
+		return op
+	}
+	return ParseWalletOp(body)
+}
+
+// parseOp разбирает операцию jetton во входящем сообщении транзакции
+// (например, mint в том же сообщении, что и StateInit).
+func parseOp(tx *tlb.Transaction) *JettonOp {
+	op := ParseJettonOp(inboundBody(tx))
+	if op != nil {
+		op.Aborted = txAborted(tx)
+	}
+	return op
+}
+
+// inboundBody возвращает тело входящего сообщения транзакции.
+func inboundBody(tx *tlb.Transaction) *cell.Cell {
+	if tx == nil || tx.IO.In == nil || tx.IO.In.Msg == nil {
+		return nil
+	}
+	return tx.IO.In.Msg.Payload()
+}
+
+// txAborted сообщает, что обычная транзакция откатилась.
+func txAborted(tx *tlb.Transaction) bool {
+	if desc, ok := tx.Description.Description.(tlb.TransactionDescriptionOrdinary); ok {
+		return desc.Aborted
+	}
+	return false
+}
+
+// addrString форматирует адрес в raw-виде; addr_none — пустая строка.
+func addrString(addr *address.Address) string {
+	if addr == nil || addr.IsAddrNone() {
+		return ""
+	}
+	return RawAddress(addr)
+}